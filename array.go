@@ -0,0 +1,328 @@
+package jsonnav
+
+import (
+	"slices"
+	"sync"
+)
+
+// array is a json array within a document.
+//
+// The items are read from and written to the location of the array in its parent, so the changes made through any
+// array obtained from a document are reflected in the document, as they are for objects. All the arrays obtained for
+// the same location are the same value, so changes to the length are visible through all of them.
+type array struct {
+	loc location
+	// last contains the items last read from the location
+	last []any
+	// refs contains the arrays obtained from the document
+	refs *references
+}
+
+var _ Value = (*array)(nil)
+
+// references contains the arrays obtained from a document by their location, so the same location returns the same
+// array while it holds the same items.
+type references struct {
+	mu     sync.Mutex
+	arrays map[arraySlot]*array
+}
+
+// arraySlot identifies the location of an array within its parent.
+type arraySlot struct {
+	// parent is the pointer to the object map or the array containing the array
+	parent any
+	key    string
+	index  int
+}
+
+// array returns the array previously obtained for the slot when it still holds the items, otherwise it returns a
+// new array for the location.
+func (r *references) array(slot arraySlot, items []any, loc location) *array {
+	if r == nil {
+		return &array{loc: loc, last: items}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if a, ok := r.arrays[slot]; ok && a.holds(items) {
+		return a
+	}
+	if r.arrays == nil {
+		r.arrays = make(map[arraySlot]*array)
+	}
+	a := &array{loc: loc, last: items, refs: r}
+	r.arrays[slot] = a
+	return a
+}
+
+// location is the position of a raw json value within its parent.
+type location interface {
+	load() (any, bool)
+	store(rawValue any)
+}
+
+// keyLocation is the position of a value within an object.
+type keyLocation struct {
+	m   *Map
+	key string
+}
+
+func (l *keyLocation) load() (any, bool) {
+	rawValue, ok := l.m.m[l.key]
+	return rawValue, ok
+}
+
+func (l *keyLocation) store(rawValue any) {
+	l.m.m[l.key] = rawValue
+}
+
+// indexLocation is the position of a value within an array.
+type indexLocation struct {
+	parent *array
+	index  int
+}
+
+func (l *indexLocation) load() (any, bool) {
+	items := l.parent.items()
+	if l.index >= len(items) {
+		return nil, false
+	}
+	return items[l.index], true
+}
+
+func (l *indexLocation) store(rawValue any) {
+	items := l.parent.items()
+	if l.index >= len(items) {
+		return
+	}
+	// Items are never modified in place, as they can be shared with detached arrays
+	items = slices.Clone(items)
+	items[l.index] = rawValue
+	l.parent.store(items)
+}
+
+// rootLocation holds a value that doesn't have a parent.
+type rootLocation struct {
+	rawValue any
+}
+
+func (l *rootLocation) load() (any, bool) {
+	return l.rawValue, true
+}
+
+func (l *rootLocation) store(rawValue any) {
+	l.rawValue = rawValue
+}
+
+// newArray returns an array without parent.
+func newArray(items []any) *array {
+	return &array{loc: &rootLocation{rawValue: items}, last: items, refs: &references{}}
+}
+
+// items returns the current items of the array.
+// When the array was removed or replaced in its parent, the array is detached from the document keeping the last
+// items, as it happens with objects.
+func (a *array) items() []any {
+	rawValue, ok := a.loc.load()
+	items, isArray := rawValue.([]any)
+	if !ok || !isArray || !sameArray(items, a.last) {
+		a.loc = &rootLocation{rawValue: a.last}
+	}
+	return a.last
+}
+
+// holds determines whether the array is still in its location containing the items.
+func (a *array) holds(items []any) bool {
+	current := a.items()
+	_, detached := a.loc.(*rootLocation)
+	return !detached && sameArray(current, items)
+}
+
+// store sets the items of the array in its location.
+func (a *array) store(items []any) {
+	a.loc.store(items)
+	a.last = items
+}
+
+// sameArray determines whether both slices share the same backing array.
+// Empty arrays can't be told apart so they are considered the same.
+func sameArray(a, b []any) bool {
+	return len(a) == len(b) && (len(a) == 0 || &a[0] == &b[0])
+}
+
+// child returns the Value of the item at the index, referencing its location for arrays.
+func (a *array) child(index int, rawValue any) Value {
+	switch v := rawValue.(type) {
+	case []any:
+		return a.refs.array(arraySlot{parent: a, index: index}, v, &indexLocation{parent: a, index: index})
+	case map[string]any:
+		return &Map{m: v, refs: a.refs}
+	default:
+		return mustToPathValue(rawValue)
+	}
+}
+
+// slice returns the items as a Slice.
+func (a *array) slice() Slice {
+	items := a.items()
+	result := make(Slice, len(items))
+	for i, item := range items {
+		result[i] = a.child(i, item)
+	}
+	return result
+}
+
+// Exists returns true for arrays.
+func (a *array) Exists() bool {
+	return true
+}
+
+// IsEmpty returns true if the array does not have any items.
+func (a *array) IsEmpty() bool {
+	return len(a.items()) == 0
+}
+
+// IsNull returns false for arrays.
+func (a *array) IsNull() bool {
+	return false
+}
+
+// IsArray returns true for arrays.
+func (a *array) IsArray() bool {
+	return true
+}
+
+// IsObject returns false for arrays.
+func (a *array) IsObject() bool {
+	return false
+}
+
+// IsString returns false for arrays.
+func (a *array) IsString() bool {
+	return false
+}
+
+// IsFloat returns false for arrays.
+func (a *array) IsFloat() bool {
+	return false
+}
+
+// IsBool returns false for arrays.
+func (a *array) IsBool() bool {
+	return false
+}
+
+// IsInt returns false for arrays.
+func (a *array) IsInt() bool {
+	return false
+}
+
+// Bool returns false for arrays.
+func (a *array) Bool() bool {
+	return false
+}
+
+// Float returns 0 for arrays.
+func (a *array) Float() float64 {
+	return 0
+}
+
+// Int returns 0 for arrays.
+func (a *array) Int() int64 {
+	return 0
+}
+
+// Uint returns 0 for arrays.
+func (a *array) Uint() uint64 {
+	return 0
+}
+
+// FloatE returns an ErrTypeMismatch error for arrays.
+func (a *array) FloatE() (float64, error) {
+	return Slice(nil).FloatE()
+}
+
+// IntE returns an ErrTypeMismatch error for arrays.
+func (a *array) IntE() (int64, error) {
+	return Slice(nil).IntE()
+}
+
+// UintE returns an ErrTypeMismatch error for arrays.
+func (a *array) UintE() (uint64, error) {
+	return Slice(nil).UintE()
+}
+
+// String returns an empty string for arrays.
+func (a *array) String() string {
+	return ""
+}
+
+// Value returns the values of the items as a slice.
+func (a *array) Value() any {
+	return a.slice().Value()
+}
+
+// Clone returns a deep copy of the array, detached from the document.
+func (a *array) Clone() Value {
+	return newArray(cloneItems(a.items()))
+}
+
+// Raw returns the compact json encoding of the array.
+func (a *array) Raw() []byte {
+	return rawJSON(a)
+}
+
+// Decode stores the array in the Go value pointed by target.
+func (a *array) Decode(target any) error {
+	return decodeValue(a, target)
+}
+
+// Get searches for the specified path within the array.
+func (a *array) Get(path string) Value {
+	return getPath(a, path)
+}
+
+func (a *array) get(c *component, next []component) Value {
+	switch {
+	case c.kind == countComponent:
+		return getComponents(&scalar{v: float64(len(a.items()))}, next)
+	case c.kind == keyComponent && c.index != -1:
+		items := a.items()
+		if c.index >= len(items) {
+			return undefinedScalar
+		}
+		return getComponents(a.child(c.index, items[c.index]), next)
+	default:
+		return a.slice().get(c, next)
+	}
+}
+
+// Set sets the value at the specified path, modifying the array in the document.
+func (a *array) Set(path string, rawValue any) Value {
+	return setPath(a, path, rawValue)
+}
+
+// SetRaw parses the json text and sets it at the specified path.
+func (a *array) SetRaw(path string, jsonText string) (Value, error) {
+	return setRawPath(a, path, jsonText, decodeOptions{})
+}
+
+func (a *array) set(components []component, rawValue any) Value {
+	result := a.slice().setItems(components, rawValue)
+	a.store(result.rawItems())
+	return a
+}
+
+// Delete removes the value at the specified path, modifying the array in the document.
+func (a *array) Delete(path string) Value {
+	return a.Set(path, deleteValue)
+}
+
+// Array returns the items, arrays within the items reference their location in this array.
+func (a *array) Array() Slice {
+	return a.slice()
+}
+
+// Map returns an empty map for arrays.
+func (a *array) Map() map[string]Value {
+	return map[string]Value{}
+}
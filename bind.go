@@ -0,0 +1,586 @@
+package jsonnav
+
+import (
+	"cmp"
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DecodeError describes a value that can not be decoded into the target Go type.
+type DecodeError struct {
+	// Path is the path of the value that could not be decoded, relative to the decoded value.
+	Path string
+	// Reason describes the error, for example "expected string, got number".
+	Reason string
+	// Err is ErrTypeMismatch, ErrOverflow, ErrNotInteger or the error returned by an unmarshaler.
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	if e.Path == "" {
+		return e.Reason
+	}
+	return e.Path + ": " + e.Reason
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// prefix adds the key at the beginning of the path.
+func (e *DecodeError) prefix(key string) *DecodeError {
+	if e.Path == "" {
+		e.Path = Escape(key)
+	} else {
+		e.Path = Escape(key) + "." + e.Path
+	}
+	return e
+}
+
+// As decodes the value into a new instance of T, see Value.Decode().
+func As[T any](value Value) (T, error) {
+	var result T
+	err := value.Decode(&result)
+	return result, err
+}
+
+var valueType = reflect.TypeFor[Value]()
+
+// decodeValue stores the value in the Go value pointed by target.
+func decodeValue(value Value, target any) error {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("decode target must be a non-nil pointer, got %T", target)
+	}
+	if !value.Exists() {
+		return ErrNotFound
+	}
+	if err := bind(toRawValue(value), rv.Elem()); err != nil {
+		return err
+	}
+	return nil
+}
+
+// bind sets the Go value from the raw json value, walking the raw json tree.
+func bind(rawValue any, rv reflect.Value) *DecodeError {
+	if rv.Type() == valueType {
+		rv.Set(reflect.ValueOf(mustToPathValue(rawValue)))
+		return nil
+	}
+	if rv.Kind() == reflect.Pointer {
+		if rawValue == nil {
+			rv.SetZero()
+			return nil
+		}
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		return bind(rawValue, rv.Elem())
+	}
+	if err, ok := bindUnmarshaler(rawValue, rv); ok {
+		return err
+	}
+	if rawValue == nil {
+		switch rv.Kind() {
+		case reflect.Interface, reflect.Map, reflect.Slice:
+			rv.SetZero()
+		default:
+			// As with encoding/json, null has no effect on other types
+		}
+		return nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Interface:
+		if rv.NumMethod() > 0 {
+			return typeMismatch(rawValue, rv.Type())
+		}
+		rv.Set(reflect.ValueOf(plainValue(rawValue)))
+	case reflect.Bool:
+		b, ok := rawValue.(bool)
+		if !ok {
+			return typeMismatch(rawValue, rv.Type())
+		}
+		rv.SetBool(b)
+	case reflect.String:
+		if rv.Type() == numberType {
+			return bindJSONNumber(rawValue, rv)
+		}
+		s, ok := rawValue.(string)
+		if !ok {
+			return typeMismatch(rawValue, rv.Type())
+		}
+		rv.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return bindNumber(rawValue, rv)
+	case reflect.Slice:
+		return bindSlice(rawValue, rv)
+	case reflect.Array:
+		return bindArray(rawValue, rv)
+	case reflect.Map:
+		return bindMap(rawValue, rv)
+	case reflect.Struct:
+		return bindStruct(rawValue, rv)
+	default:
+		return &DecodeError{Reason: fmt.Sprintf("unsupported type %s", rv.Type()), Err: ErrTypeMismatch}
+	}
+	return nil
+}
+
+// bindUnmarshaler uses the json.Unmarshaler or encoding.TextUnmarshaler implementation of the Go value, returning
+// false when the type doesn't implement them.
+func bindUnmarshaler(rawValue any, rv reflect.Value) (*DecodeError, bool) {
+	if !rv.CanAddr() {
+		return nil, false
+	}
+
+	var err error
+	switch unmarshaler := rv.Addr().Interface().(type) {
+	case json.Unmarshaler:
+		var blob []byte
+		if blob, err = json.Marshal(rawValue); err == nil {
+			err = unmarshaler.UnmarshalJSON(blob)
+		}
+	case encoding.TextUnmarshaler:
+		s, ok := rawValue.(string)
+		if !ok {
+			return typeMismatch(rawValue, rv.Type()), true
+		}
+		err = unmarshaler.UnmarshalText([]byte(s))
+	default:
+		return nil, false
+	}
+
+	if err != nil {
+		return &DecodeError{Reason: err.Error(), Err: err}, true
+	}
+	return nil, true
+}
+
+func bindNumber(rawValue any, rv reflect.Value) *DecodeError {
+	switch rawValue.(type) {
+	case float64, json.Number:
+	default:
+		return typeMismatch(rawValue, rv.Type())
+	}
+
+	s := &scalar{v: rawValue}
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := s.IntE()
+		if err != nil {
+			return &DecodeError{Reason: err.Error(), Err: err}
+		}
+		if rv.OverflowInt(n) {
+			return overflow(n, rv.Type())
+		}
+		rv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := s.UintE()
+		if err != nil {
+			return &DecodeError{Reason: err.Error(), Err: err}
+		}
+		if rv.OverflowUint(n) {
+			return overflow(n, rv.Type())
+		}
+		rv.SetUint(n)
+	default:
+		n, err := s.FloatE()
+		if err != nil {
+			return &DecodeError{Reason: err.Error(), Err: err}
+		}
+		if rv.OverflowFloat(n) {
+			return overflow(n, rv.Type())
+		}
+		rv.SetFloat(n)
+	}
+	return nil
+}
+
+// bindJSONNumber sets a json.Number from numbers and strings containing a valid number, as encoding/json does.
+func bindJSONNumber(rawValue any, rv reflect.Value) *DecodeError {
+	var text string
+	switch v := rawValue.(type) {
+	case float64:
+		text = string((&scalar{v: v}).Raw())
+	case json.Number:
+		text = v.String()
+	case string:
+		if !isNumberText(v) {
+			return &DecodeError{Reason: fmt.Sprintf("invalid number %q", v), Err: ErrTypeMismatch}
+		}
+		text = v
+	default:
+		return typeMismatch(rawValue, rv.Type())
+	}
+	rv.SetString(text)
+	return nil
+}
+
+// bindQuoted sets the value of a field with the `string` tag option, where scalars are encoded within json strings.
+func bindQuoted(rawValue any, rv reflect.Value) *DecodeError {
+	s, ok := rawValue.(string)
+	if !ok {
+		if rawValue == nil {
+			return bind(nil, rv)
+		}
+		return &DecodeError{
+			Reason: fmt.Sprintf("invalid use of the string tag option, expected a string, got %s", jsonType(rawValue)),
+			Err:    ErrTypeMismatch,
+		}
+	}
+
+	quoted, err := unmarshalRaw([]byte(s), decodeOptions{useNumber: true})
+	if err != nil {
+		return &DecodeError{Reason: fmt.Sprintf("invalid use of the string tag option for %q", s), Err: err}
+	}
+	return bind(quoted, rv)
+}
+
+func bindSlice(rawValue any, rv reflect.Value) *DecodeError {
+	items, ok := rawValue.([]any)
+	if !ok {
+		if s, isString := rawValue.(string); isString && rv.Type().Elem().Kind() == reflect.Uint8 {
+			// Byte slices are encoded as base64 strings
+			b, err := base64.StdEncoding.DecodeString(s)
+			if err != nil {
+				return &DecodeError{Reason: "invalid base64 string", Err: err}
+			}
+			rv.SetBytes(b)
+			return nil
+		}
+		return typeMismatch(rawValue, rv.Type())
+	}
+
+	slice := reflect.MakeSlice(rv.Type(), len(items), len(items))
+	for i, item := range items {
+		if err := bind(item, slice.Index(i)); err != nil {
+			return err.prefix(strconv.Itoa(i))
+		}
+	}
+	rv.Set(slice)
+	return nil
+}
+
+func bindArray(rawValue any, rv reflect.Value) *DecodeError {
+	items, ok := rawValue.([]any)
+	if !ok {
+		return typeMismatch(rawValue, rv.Type())
+	}
+
+	for i := range rv.Len() {
+		if i >= len(items) {
+			rv.Index(i).SetZero()
+			continue
+		}
+		if err := bind(items[i], rv.Index(i)); err != nil {
+			return err.prefix(strconv.Itoa(i))
+		}
+	}
+	return nil
+}
+
+func bindMap(rawValue any, rv reflect.Value) *DecodeError {
+	m, ok := objectMap(rawValue)
+	if !ok {
+		return typeMismatch(rawValue, rv.Type())
+	}
+
+	t := rv.Type()
+	if rv.IsNil() {
+		rv.Set(reflect.MakeMapWithSize(t, len(m)))
+	}
+	for key, item := range m {
+		keyValue, err := mapKey(key, t.Key())
+		if err != nil {
+			return err.prefix(key)
+		}
+		elem := reflect.New(t.Elem()).Elem()
+		if err := bind(item, elem); err != nil {
+			return err.prefix(key)
+		}
+		rv.SetMapIndex(keyValue, elem)
+	}
+	return nil
+}
+
+// mapKey converts the object key to the key type of a Go map.
+func mapKey(key string, t reflect.Type) (reflect.Value, *DecodeError) {
+	keyPointer := reflect.New(t)
+	if unmarshaler, ok := keyPointer.Interface().(encoding.TextUnmarshaler); ok {
+		if err := unmarshaler.UnmarshalText([]byte(key)); err != nil {
+			return reflect.Value{}, &DecodeError{Reason: err.Error(), Err: err}
+		}
+		return keyPointer.Elem(), nil
+	}
+
+	keyValue := keyPointer.Elem()
+	switch t.Kind() {
+	case reflect.String:
+		keyValue.SetString(key)
+		return keyValue, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(key, 10, 64)
+		if err == nil && !keyValue.OverflowInt(n) {
+			keyValue.SetInt(n)
+			return keyValue, nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(key, 10, 64)
+		if err == nil && !keyValue.OverflowUint(n) {
+			keyValue.SetUint(n)
+			return keyValue, nil
+		}
+	default:
+	}
+	return reflect.Value{}, &DecodeError{Reason: fmt.Sprintf("invalid key for %s", t), Err: ErrTypeMismatch}
+}
+
+func bindStruct(rawValue any, rv reflect.Value) *DecodeError {
+	m, ok := objectMap(rawValue)
+	if !ok {
+		return typeMismatch(rawValue, rv.Type())
+	}
+
+	for _, field := range structFields(rv.Type()) {
+		key := field.name
+		item, found := m[key]
+		if !found {
+			// Match keys case-insensitively as with encoding/json
+			for k, v := range m {
+				if strings.EqualFold(k, field.name) {
+					key, item, found = k, v, true
+					break
+				}
+			}
+		}
+		if !found {
+			continue
+		}
+		bindField := bind
+		if field.quoted {
+			bindField = bindQuoted
+		}
+		if err := bindField(item, fieldByIndex(rv, field.index)); err != nil {
+			return err.prefix(key)
+		}
+	}
+	return nil
+}
+
+// fieldByIndex returns the nested struct field, allocating the embedded struct pointers.
+func fieldByIndex(rv reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				rv.Set(reflect.New(rv.Type().Elem()))
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+	return rv
+}
+
+// structField represents a struct field that can be decoded or encoded.
+type structField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+	tagged    bool
+	// quoted is set for scalar fields with the string tag option, that are encoded within json strings.
+	quoted bool
+}
+
+// structFieldsCache contains the []structField for each struct type.
+var structFieldsCache sync.Map
+
+// structFields returns the fields of the struct type that can be decoded or encoded, named according to the json
+// struct tags. Fields of embedded structs are promoted following the encoding/json rules, see dominantField().
+func structFields(t reflect.Type) []structField {
+	if cached, ok := structFieldsCache.Load(t); ok {
+		if fields, ok := cached.([]structField); ok {
+			return fields
+		}
+	}
+
+	fields := appendStructFields(nil, t, nil)
+	slices.SortStableFunc(fields, func(a, b structField) int {
+		return cmp.Compare(len(a.index), len(b.index))
+	})
+	var names []string
+	byName := make(map[string][]structField, len(fields))
+	for _, f := range fields {
+		if _, ok := byName[f.name]; !ok {
+			names = append(names, f.name)
+		}
+		byName[f.name] = append(byName[f.name], f)
+	}
+	result := make([]structField, 0, len(names))
+	for _, name := range names {
+		if f, ok := dominantField(byName[name]); ok {
+			result = append(result, f)
+		}
+	}
+
+	structFieldsCache.Store(t, result)
+	return result
+}
+
+// dominantField returns the field that is used among the fields with the same name, sorted by depth: the shallowest
+// one, or the tagged one when there are many at the same depth. It returns false when the fields are ambiguous,
+// in which case they are ignored as with encoding/json.
+func dominantField(fields []structField) (structField, bool) {
+	depth := len(fields[0].index)
+	var dominant []structField
+	for _, f := range fields {
+		if len(f.index) > depth {
+			break
+		}
+		dominant = append(dominant, f)
+	}
+	if len(dominant) == 1 {
+		return dominant[0], true
+	}
+	dominant = slices.DeleteFunc(dominant, func(f structField) bool {
+		return !f.tagged
+	})
+	if len(dominant) == 1 {
+		return dominant[0], true
+	}
+	return structField{}, false
+}
+
+func appendStructFields(fields []structField, t reflect.Type, index []int) []structField {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		fieldIndex := append(slices.Clip(index), i)
+
+		if f.Anonymous && name == "" {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Pointer {
+				if !f.IsExported() {
+					// Unexported embedded pointers can't be allocated
+					continue
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				fields = appendStructFields(fields, embedded, fieldIndex)
+				continue
+			}
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+		tagged := name != ""
+		if !tagged {
+			name = f.Name
+		}
+		optionList := strings.Split(options, ",")
+		fields = append(fields, structField{
+			name:      name,
+			index:     fieldIndex,
+			omitEmpty: slices.Contains(optionList, "omitempty"),
+			tagged:    tagged,
+			quoted:    slices.Contains(optionList, "string") && isQuotable(f.Type),
+		})
+	}
+	return fields
+}
+
+// isQuotable determines whether the string tag option applies to the field type, as with encoding/json it applies
+// to strings, numbers and booleans.
+func isQuotable(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer && t.Name() == "" {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.String:
+		return true
+	default:
+		return false
+	}
+}
+
+// objectMap returns the Go map of a raw json object.
+func objectMap(rawValue any) (map[string]any, bool) {
+	switch v := rawValue.(type) {
+	case map[string]any:
+		return v, true
+	case *Map:
+		return v.m, true
+	default:
+		return nil, false
+	}
+}
+
+func typeMismatch(rawValue any, t reflect.Type) *DecodeError {
+	return &DecodeError{
+		Reason: fmt.Sprintf("expected %s, got %s", jsonTypeOf(t), jsonType(rawValue)),
+		Err:    ErrTypeMismatch,
+	}
+}
+
+func overflow(n any, t reflect.Type) *DecodeError {
+	return &DecodeError{Reason: fmt.Sprintf("number %v overflows %s", n, t), Err: ErrOverflow}
+}
+
+// jsonTypeOf returns the json type expected for the Go type.
+func jsonTypeOf(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	default:
+		return t.String()
+	}
+}
+
+// jsonType returns the json type of the raw json value.
+func jsonType(rawValue any) string {
+	switch rawValue.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64, json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any, *Map:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", rawValue)
+	}
+}
@@ -0,0 +1,256 @@
+package jsonnav
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type instrument struct {
+	Name    string   `json:"name"`
+	Strings *int     `json:"strings,omitempty"`
+	Tags    []string `json:"tags"`
+}
+
+type band struct {
+	Name        string         `json:"name"`
+	Founded     uint16         `json:"founded"`
+	Active      bool           `json:"active"`
+	Rating      float32        `json:"rating"`
+	Instruments []instrument   `json:"instruments"`
+	Members     map[string]int `json:"members"`
+	Extra       any            `json:"extra"`
+	Ignored     string         `json:"-"`
+}
+
+type member struct {
+	Person
+	*Address
+	Name string `json:"fullName"`
+}
+
+type Person struct {
+	Name string
+	Age  int
+}
+
+type Address struct {
+	City string `json:"city"`
+}
+
+type Label struct {
+	Name string
+}
+
+type Publisher struct {
+	Name string `json:"Name"`
+}
+
+type ambiguous struct {
+	Person
+	Label
+}
+
+type tagged struct {
+	Label
+	Publisher
+}
+
+func TestValueDecode(t *testing.T) {
+	const doc = `{"name": "Queen", "founded": 1970, "active": false, "rating": 4.5, "Ignored": "x",
+		"instruments": [{"name": "guitar", "strings": 6, "tags": ["electric"]}, {"name": "drums", "tags": null}],
+		"members": {"Freddie": 1946, "Brian": 1947}, "extra": {"a": [1, "b"]}}`
+
+	t.Run("should decode into structs", func(t *testing.T) {
+		var result band
+		require.NoError(t, must(Unmarshal(doc)).Decode(&result))
+		six := 6
+		require.Equal(t, band{
+			Name:        "Queen",
+			Founded:     1970,
+			Rating:      4.5,
+			Instruments: []instrument{{Name: "guitar", Strings: &six, Tags: []string{"electric"}}, {Name: "drums"}},
+			Members:     map[string]int{"Freddie": 1946, "Brian": 1947},
+			Extra:       map[string]any{"a": []any{1.0, "b"}},
+		}, result)
+	})
+
+	t.Run("should decode values of any kind", func(t *testing.T) {
+		value := must(Unmarshal(doc))
+		name, err := As[string](value.Get("name"))
+		require.NoError(t, err)
+		require.Equal(t, "Queen", name)
+
+		founded, err := As[*int64](value.Get("founded"))
+		require.NoError(t, err)
+		require.Equal(t, int64(1970), *founded)
+
+		names, err := As[[]string](value.Get("instruments.#.name"))
+		require.NoError(t, err)
+		require.Equal(t, []string{"guitar", "drums"}, names)
+
+		arr, err := As[[3]string](value.Get("instruments.#.name"))
+		require.NoError(t, err)
+		require.Equal(t, [3]string{"guitar", "drums", ""}, arr)
+
+		years, err := As[map[string]uint](value.Get("members"))
+		require.NoError(t, err)
+		require.Equal(t, map[string]uint{"Freddie": 1946, "Brian": 1947}, years)
+
+		extra, err := As[map[string]Value](value.Get("extra"))
+		require.NoError(t, err)
+		require.Equal(t, "b", extra["a"].Get("1").String())
+
+		keys, err := As[map[int]bool](FromAny(map[string]any{"1": true, "-2": false}))
+		require.NoError(t, err)
+		require.Equal(t, map[int]bool{1: true, -2: false}, keys)
+	})
+
+	t.Run("should report the path of the value that could not be decoded", func(t *testing.T) {
+		value := must(Unmarshal(`{"instruments": [{"name": "guitar"}, {"name": 1}]}`))
+		_, err := As[band](value)
+		require.EqualError(t, err, "instruments.1.name: expected string, got number")
+		require.ErrorIs(t, err, ErrTypeMismatch)
+
+		var decodeErr *DecodeError
+		require.ErrorAs(t, err, &decodeErr)
+		require.Equal(t, "instruments.1.name", decodeErr.Path)
+		require.Equal(t, value.Get(decodeErr.Path).Value(), 1.0)
+
+		_, err = As[map[string]int8](must(Unmarshal(`{"a.b": 300}`)))
+		require.EqualError(t, err, `a\.b: number 300 overflows int8`)
+		require.ErrorIs(t, err, ErrOverflow)
+
+		_, err = As[[]int](must(Unmarshal(`[1, 2.5]`)))
+		require.ErrorIs(t, err, ErrNotInteger)
+		require.True(t, strings.HasPrefix(err.Error(), "1: "))
+
+		_, err = As[uint](must(Unmarshal(`-1`)))
+		require.ErrorIs(t, err, ErrOverflow)
+
+		_, err = As[band](must(Unmarshal(`[]`)))
+		require.EqualError(t, err, "expected object, got array")
+
+		_, err = As[[]band](must(Unmarshal(`[{"active": "yes"}]`)))
+		require.EqualError(t, err, "0.active: expected boolean, got string")
+
+		_, err = As[map[int]bool](must(Unmarshal(`{"a": true}`)))
+		require.EqualError(t, err, "a: invalid key for int")
+	})
+
+	t.Run("should follow the encoding/json rules", func(t *testing.T) {
+		value := must(Unmarshal(`{"NAME": "Brian", "age": 76, "city": "London", "fullName": "Brian May"}`))
+		result, err := As[member](value)
+		require.NoError(t, err)
+		require.Equal(t, member{Person: Person{Name: "Brian", Age: 76}, Address: &Address{City: "London"},
+			Name: "Brian May"}, result)
+
+		result = member{Person: Person{Age: 1}}
+		require.NoError(t, must(Unmarshal(`{"Age": null, "Address": null}`)).Decode(&result))
+		require.Equal(t, 1, result.Age)
+
+		amb, err := As[ambiguous](must(Unmarshal(`{"Name": "x", "Age": 1}`)))
+		require.NoError(t, err)
+		require.Equal(t, ambiguous{Person: Person{Age: 1}}, amb)
+		str, err := Marshal(FromAny(ambiguous{Person{"a", 1}, Label{"b"}}))
+		require.NoError(t, err)
+		require.Equal(t, `{"Age":1}`, str)
+
+		tag, err := As[tagged](must(Unmarshal(`{"Name": "x"}`)))
+		require.NoError(t, err)
+		require.Equal(t, tagged{Publisher: Publisher{Name: "x"}}, tag)
+		str, err = Marshal(FromAny(tagged{Label{"a"}, Publisher{"b"}}))
+		require.NoError(t, err)
+		require.Equal(t, `{"Name":"b"}`, str)
+
+		data, err := As[[]byte](FromAny("aGVsbG8="))
+		require.NoError(t, err)
+		require.Equal(t, "hello", string(data))
+	})
+
+	t.Run("should decode json numbers", func(t *testing.T) {
+		var result struct {
+			ID    json.Number  `json:"id"`
+			Text  json.Number  `json:"text"`
+			Exact *json.Number `json:"exact"`
+		}
+		require.NoError(t, MustUnmarshalMap(`{"id": 5, "text": "1.5"}`).Decode(&result))
+		require.Equal(t, json.Number("5"), result.ID)
+		require.Equal(t, json.Number("1.5"), result.Text)
+
+		value := must(Unmarshal(`{"exact": 9007199254740993}`, UseNumber()))
+		require.NoError(t, value.Decode(&result))
+		require.Equal(t, json.Number("9007199254740993"), *result.Exact)
+
+		err := MustUnmarshalMap(`{"id": "abc"}`).Decode(&result)
+		require.ErrorIs(t, err, ErrTypeMismatch)
+		err = MustUnmarshalMap(`{"id": true}`).Decode(&result)
+		require.EqualError(t, err, "id: expected string, got boolean")
+	})
+
+	t.Run("should support the string tag option", func(t *testing.T) {
+		type quoted struct {
+			ID     int64   `json:"id,string"`
+			Active *bool   `json:"active,string"`
+			Name   string  `json:"name,string"`
+			Tags   []int   `json:"tags,string"`
+			Score  float64 `json:"score,string,omitempty"`
+		}
+		value := MustUnmarshalMap(`{"id": "9007199254740993", "active": "true", "name": "\"x\"", "tags": [1]}`)
+		result, err := As[quoted](value)
+		require.NoError(t, err)
+		active := true
+		require.Equal(t, quoted{ID: 9007199254740993, Active: &active, Name: "x", Tags: []int{1}}, result)
+
+		str, err := Marshal(FromAny(result))
+		require.NoError(t, err)
+		expected, err := json.Marshal(result)
+		require.NoError(t, err)
+		require.JSONEq(t, string(expected), str)
+
+		_, err = As[quoted](MustUnmarshalMap(`{"id": 1}`))
+		require.ErrorIs(t, err, ErrTypeMismatch)
+		_, err = As[quoted](MustUnmarshalMap(`{"name": "x"}`))
+		var decodeErr *DecodeError
+		require.ErrorAs(t, err, &decodeErr)
+		require.Equal(t, "name", decodeErr.Path)
+		_, err = As[quoted](MustUnmarshalMap(`{"active": null}`))
+		require.NoError(t, err)
+	})
+
+	t.Run("should use unmarshalers", func(t *testing.T) {
+		ts, err := As[time.Time](FromAny("2024-01-02T03:04:05Z"))
+		require.NoError(t, err)
+		require.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), ts)
+
+		_, err = As[struct{ At time.Time }](must(Unmarshal(`{"at": "yesterday"}`)))
+		var decodeErr *DecodeError
+		require.ErrorAs(t, err, &decodeErr)
+		require.Equal(t, "at", decodeErr.Path)
+	})
+
+	t.Run("should decode lazy values and options", func(t *testing.T) {
+		value, err := UnmarshalLazy([]byte(doc), UseNumber())
+		require.NoError(t, err)
+		result, err := As[band](value)
+		require.NoError(t, err)
+		require.Equal(t, "drums", result.Instruments[1].Name)
+
+		ordered := must(Unmarshal(`{"b": 1, "a": {"c": 2}}`, PreserveOrder()))
+		m, err := As[map[string]any](ordered)
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"b": 1.0, "a": map[string]any{"c": 2.0}}, m)
+	})
+
+	t.Run("should return an error for undefined values and invalid targets", func(t *testing.T) {
+		_, err := As[string](must(Unmarshal(doc)).Get("missing"))
+		require.ErrorIs(t, err, ErrNotFound)
+
+		var result band
+		require.Error(t, must(Unmarshal(doc)).Decode(result))
+		require.Error(t, must(Unmarshal(doc)).Decode(nil))
+	})
+}
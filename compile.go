@@ -0,0 +1,435 @@
+package jsonnav
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Path is a compiled path expression that can be evaluated against multiple values without parsing it again.
+// A Path is safe for concurrent use.
+type Path struct {
+	path       string
+	components []component
+}
+
+type componentKind int
+
+const (
+	// keyComponent represents an object key or an array index. Object keys can contain wildcards.
+	keyComponent componentKind = iota
+	// countComponent represents `#`, the number of elements in an array.
+	countComponent
+	// mapComponent represents `#.`, the sub components are applied to each element of an array.
+	mapComponent
+	// queryComponent represents `#(...)` or `#(...)#`.
+	queryComponent
+	// conditionComponent represents a condition on an object key like `age==45`, that returns the object itself.
+	conditionComponent
+	// modifierComponent represents a modifier like `@reverse`.
+	modifierComponent
+	// multipathComponent represents a new object `{...}` or array `[...]` built from multiple paths.
+	multipathComponent
+)
+
+// component represents a parsed path component.
+type component struct {
+	kind componentKind
+	// path is the raw path starting at this component, used for Value implementations outside the package.
+	path string
+	// key is the unescaped object key or the wildcard pattern when wildcard is set.
+	key      string
+	wildcard bool
+	// index is the array index represented by the key, -1 when the key is not an index.
+	index int
+	// sub contains the components applied to each element of an array, up to the next pipe.
+	sub       []component
+	query     *query
+	cond      *condition
+	modifier  ModifierFunc
+	arg       Value
+	multipath *multipath
+}
+
+// PathError describes a syntax error in a path expression.
+type PathError struct {
+	// Path is the path expression.
+	Path string
+	// Offset is the position in bytes within Path where the error was found.
+	Offset int
+	// Reason describes the error.
+	Reason string
+}
+
+func (e *PathError) Error() string {
+	return fmt.Sprintf("invalid path %q at offset %d: %s", e.Path, e.Offset, e.Reason)
+}
+
+// Compile parses the path expression, so it can be evaluated multiple times.
+// It returns a *PathError when the path expression is not valid.
+func Compile(path string) (*Path, error) {
+	components, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Path{path: path, components: components}, nil
+}
+
+// Validate checks the syntax of the path expression, returning a *PathError when it's not valid.
+func Validate(path string) error {
+	_, err := parsePath(path)
+	return err
+}
+
+// MustCompile is like Compile but panics if the path expression can not be parsed.
+// It's used for static variables and tests.
+func MustCompile(path string) *Path {
+	return must(Compile(path))
+}
+
+// String returns the source path expression.
+func (p *Path) String() string {
+	return p.path
+}
+
+// Get searches for the path within the value.
+func (p *Path) Get(value Value) Value {
+	return getComponents(value, p.components)
+}
+
+// Set sets the value at the path and returns the modified instance.
+// If the path does not exist, it will be created.
+func (p *Path) Set(value Value, rawValue any) Value {
+	rawValue, err := toSetValue(rawValue)
+	if err != nil {
+		return value
+	}
+	return setComponents(value, p.components, rawValue)
+}
+
+// Delete deletes the value at the path and returns the modified instance.
+func (p *Path) Delete(value Value) Value {
+	return setComponents(value, p.components, deleteValue)
+}
+
+// getPath parses and evaluates the path, returning an undefined value when the path is not valid.
+func getPath(value Value, path string) Value {
+	components, err := parsePath(path)
+	if err != nil {
+		return undefinedScalar
+	}
+	return getComponents(value, components)
+}
+
+// setPath parses the path and sets the value at the path. It's a noop when the path is not valid.
+func setPath(value Value, path string, rawValue any) Value {
+	components, err := parsePath(path)
+	if err != nil {
+		return value
+	}
+	if rawValue, err = toSetValue(rawValue); err != nil {
+		return value
+	}
+	return setComponents(value, components, rawValue)
+}
+
+// setRawPath parses the json text and sets it at the path.
+// It returns a *PathError when the path is not valid.
+func setRawPath(value Value, path string, jsonText string, options decodeOptions) (Value, error) {
+	components, err := parsePath(path)
+	if err != nil {
+		return value, err
+	}
+	rawValue, err := unmarshalRaw([]byte(jsonText), options)
+	if err != nil {
+		return value, err
+	}
+	// The decoded value is already a valid json value, keeping the ordered maps when the value is ordered
+	return setComponents(value, components, rawValue), nil
+}
+
+// toSetValue converts the Go value to be set into a json value, see FromGo().
+func toSetValue(rawValue any) (any, error) {
+	if rawValue == deleteValue {
+		return rawValue, nil
+	}
+	result, _, err := normalize(rawValue)
+	return result, err
+}
+
+// getComponents evaluates the components on the value.
+func getComponents(value Value, components []component) Value {
+	if len(components) == 0 {
+		return value
+	}
+	if !value.Exists() {
+		return undefinedScalar
+	}
+
+	c, next := &components[0], components[1:]
+	switch c.kind {
+	case modifierComponent:
+		result := c.modifier(value, c.arg)
+		if result == nil {
+			return undefinedScalar
+		}
+		return getComponents(result, next)
+	case multipathComponent:
+		return getComponents(c.multipath.get(value), next)
+	default:
+		return getComponent(value, c, next)
+	}
+}
+
+// getComponent evaluates the component on the value according to its type, followed by the next components.
+func getComponent(value Value, c *component, next []component) Value {
+	switch v := value.(type) {
+	case *Map:
+		return v.get(c, next)
+	case Slice:
+		return v.get(c, next)
+	case *array:
+		return v.get(c, next)
+	case *scalar:
+		return v.get(c, next)
+	case *LazyValue:
+		return v.get(c, next)
+	default:
+		// Value implemented outside the package
+		return value.Get(c.path)
+	}
+}
+
+// setComponents sets the raw value at the path represented by the components.
+func setComponents(value Value, components []component, rawValue any) Value {
+	if len(components) == 0 {
+		return value
+	}
+
+	switch v := value.(type) {
+	case *Map:
+		return v.set(components, rawValue)
+	case Slice:
+		return v.set(components, rawValue)
+	case *array:
+		return v.set(components, rawValue)
+	case *scalar:
+		// Scalar values can't be set by path: noop
+		return v
+	case *LazyValue:
+		return v.set(components, rawValue)
+	default:
+		// Value implemented outside the package
+		return value.Set(components[0].path, rawValue)
+	}
+}
+
+// following returns the components to apply after this one when setting a value, including the mapped components.
+func (c *component) following(next []component) []component {
+	if len(c.sub) == 0 {
+		return next
+	}
+	return append(slices.Clip(c.sub), next...)
+}
+
+// parsePath parses the path expression into components.
+func parsePath(path string) ([]component, error) {
+	p := &parser{path: path}
+	if path == "" {
+		return nil, p.errorf(0, "empty path")
+	}
+	if err := p.checkBalanced(); err != nil {
+		return nil, err
+	}
+	return p.parseComponents(path, 0)
+}
+
+// parser parses path expressions, keeping the full expression to report errors.
+type parser struct {
+	path string
+}
+
+func (p *parser) errorf(offset int, format string, args ...any) error {
+	return &PathError{Path: p.path, Offset: offset, Reason: fmt.Sprintf(format, args...)}
+}
+
+// checkBalanced checks that parentheses, brackets, braces and string literals are closed and that escape
+// characters are followed by the escaped character.
+func (p *parser) checkBalanced() error {
+	var openings []int
+	for i := 0; i < len(p.path); i++ {
+		switch c := p.path[i]; c {
+		case '\\':
+			if i == len(p.path)-1 {
+				return p.errorf(i, "escape character at the end of the path")
+			}
+			i++
+		case '"':
+			end := indexClosingQuote(p.path, i)
+			if end == -1 {
+				return p.errorf(i, "unterminated string literal")
+			}
+			i = end
+		case '(', '[', '{':
+			openings = append(openings, i)
+		case ')', ']', '}':
+			if len(openings) == 0 || closingChars[p.path[openings[len(openings)-1]]] != c {
+				return p.errorf(i, "unexpected %q", c)
+			}
+			openings = openings[:len(openings)-1]
+		}
+	}
+
+	if len(openings) > 0 {
+		index := openings[len(openings)-1]
+		return p.errorf(index, "unclosed %q", p.path[index])
+	}
+	return nil
+}
+
+// closingChars contains the closing character for each opening character.
+var closingChars = map[byte]byte{'(': ')', '[': ']', '{': '}'}
+
+// parseComponents parses all the components of the path, where offset is the position of the path within the
+// full expression.
+func (p *parser) parseComponents(path string, offset int) ([]component, error) {
+	if path == "" {
+		return nil, p.errorf(offset, "empty path component")
+	}
+
+	var components []component
+	for path != "" {
+		c, remainingPath, err := p.parseComponent(path, offset)
+		if err != nil {
+			return nil, err
+		}
+		if remainingPath == "" && endsWithSeparator(path) {
+			return nil, p.errorf(offset+len(path)-1, "dangling separator %q", path[len(path)-1])
+		}
+
+		components = append(components, c)
+		offset += len(path) - len(remainingPath)
+		path = remainingPath
+	}
+	return components, nil
+}
+
+// parseComponent parses the first component of the path, returning the path remaining after it.
+func (p *parser) parseComponent(path string, offset int) (component, string, error) {
+	raw, remainingPath := splitPath(path)
+	piped := isPiped(path, raw)
+	c := component{path: path, key: raw, index: -1}
+	if raw == "" {
+		return c, "", p.errorf(offset, "empty path component")
+	}
+
+	if fn, arg, ok := cutModifier(raw); ok {
+		c.kind, c.modifier, c.arg = modifierComponent, fn, arg
+		return c, remainingPath, nil
+	}
+
+	if mp, ok, err := p.parseMultipath(raw, offset); ok {
+		c.kind, c.multipath = multipathComponent, mp
+		return c, remainingPath, err
+	}
+
+	if raw == "#" {
+		if remainingPath == "" || piped {
+			c.kind = countComponent
+			return c, remainingPath, nil
+		}
+
+		// Map over the elements up to the pipe
+		c.kind = mapComponent
+		var err error
+		c.sub, remainingPath, err = p.parseMapped(remainingPath, offset+len(raw)+1)
+		return c, remainingPath, err
+	}
+
+	if strings.HasPrefix(raw, "#(") {
+		expr, all, ok := cutQuery(raw)
+		if !ok {
+			return c, "", p.errorf(offset, "invalid query %q, expected it to end with ')' or ')#'", raw)
+		}
+		q, err := p.parseQuery(expr, offset+len("#("), all)
+		if err != nil {
+			return c, "", err
+		}
+
+		c.kind, c.query = queryComponent, q
+		if all && remainingPath != "" && !piped {
+			// Map over the matches up to the pipe
+			c.sub, remainingPath, err = p.parseMapped(remainingPath, offset+len(raw)+1)
+		}
+		return c, remainingPath, err
+	}
+
+	if index, operator := indexOperator(raw, keyOperators); index != -1 {
+		// The condition literal may contain dots (i.e. decimal numbers), use the full path to extract it
+		literal, pathAfterLiteral := cutLiteral(path[index+len(operator):])
+		cond, err := p.parseCondition(nil, operator, literal, offset+index)
+		if err != nil {
+			return c, "", err
+		}
+		c.kind, c.key, c.cond = conditionComponent, unescapeKey(raw[:index]), cond
+		return c, pathAfterLiteral, nil
+	}
+
+	c.kind = keyComponent
+	c.wildcard = hasWildcard(raw)
+	if !c.wildcard {
+		c.key = unescapeKey(raw)
+	}
+	if index, err := strconv.Atoi(c.key); err == nil && index >= 0 {
+		c.index = index
+	}
+	return c, remainingPath, nil
+}
+
+// parseMapped parses the components applied to each element of an array, up to the next pipe.
+func (p *parser) parseMapped(path string, offset int) ([]component, string, error) {
+	mappedPath, pipedPath := cutPipe(path)
+	components, err := p.parseComponents(mappedPath, offset)
+	return components, pipedPath, err
+}
+
+// parseQuery parses the expression within a query like `age>45` or `nets.#(=="fb")`.
+func (p *parser) parseQuery(expr string, offset int, all bool) (*query, error) {
+	q := &query{all: all}
+	index, operator := indexOperator(expr, queryOperators)
+	if index == -1 {
+		// Existence check
+		if expr == "" {
+			return nil, p.errorf(offset, "empty query")
+		}
+		path, err := p.parseComponents(expr, offset)
+		if err != nil {
+			return nil, err
+		}
+		q.cond = newCondition(path, "", "")
+		q.exists = true
+		q.nonEmpty = strings.HasSuffix(expr, ")#")
+		return q, nil
+	}
+
+	var path []component
+	if index > 0 {
+		var err error
+		if path, err = p.parseComponents(expr[:index], offset); err != nil {
+			return nil, err
+		}
+	}
+
+	var err error
+	q.cond, err = p.parseCondition(path, operator, expr[index+len(operator):], offset+index)
+	return q, err
+}
+
+// parseCondition validates the literal of a condition, where offset is the position of the operator.
+func (p *parser) parseCondition(path []component, operator, literal string, offset int) (*condition, error) {
+	if literal == "" {
+		return nil, p.errorf(offset, "missing value after operator %q", operator)
+	}
+	return newCondition(path, operator, literal), nil
+}
@@ -0,0 +1,133 @@
+package jsonnav
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompile(t *testing.T) {
+	t.Run("should evaluate the path on multiple values", func(t *testing.T) {
+		path, err := Compile(`friends.#(age>45)#.last`)
+		require.NoError(t, err)
+		require.Equal(t, `friends.#(age>45)#.last`, path.String())
+
+		require.Equal(t, []any{"Craig", "Murphy"}, path.Get(MustUnmarshalMap(friendsJSON)).Value())
+		require.Equal(t, []any{"Hendrix"}, path.Get(MustUnmarshalMap(`{"friends": [
+			{"first": "Jimi", "last": "Hendrix", "age": 50},
+			{"first": "Kurt", "last": "Cobain", "age": 27}
+		]}`)).Value())
+		require.Equal(t, Slice{}, path.Get(MustUnmarshalMap(`{"friends": []}`)))
+		require.False(t, path.Get(MustUnmarshalMap(`{}`)).Exists())
+	})
+
+	t.Run("should support the same syntax as get", func(t *testing.T) {
+		value := MustUnmarshalMap(friendsJSON)
+		for _, p := range []string{
+			"name.first",
+			"children.1",
+			"children.#",
+			"friends.#.first",
+			`friends.#(nets.#(=="fb"))#.first`,
+			`friends.#(last="Murphy")#|#`,
+			`friends.#(first%"J*").age`,
+			"fri*.#.nets|@flatten",
+			`{name.first,"count":children.#}`,
+			"age==37.name.last",
+		} {
+			require.Equal(t, value.Get(p), MustCompile(p).Get(value), p)
+		}
+	})
+
+	t.Run("should set and delete values", func(t *testing.T) {
+		name := MustCompile("name.middle")
+		nets := MustCompile("friends.#.nets.0")
+		value := MustUnmarshalMap(friendsJSON)
+
+		name.Set(value, "J")
+		nets.Set(value, "yt")
+		require.Equal(t, "J", value.Get("name.middle").String())
+		require.Equal(t, []any{"yt", "yt", "yt"}, value.Get("friends.#.nets.0").Value())
+
+		name.Delete(value)
+		nets.Delete(value)
+		require.False(t, value.Get("name.middle").Exists())
+		require.Equal(t, []any{"fb", "tw", "tw"}, value.Get("friends.#.nets.0").Value())
+
+		list := MustCompile("list.1.name")
+		value = list.Set(value, "second").(*Map)
+		require.Equal(t, []any{nil, map[string]any{"name": "second"}}, value.Get("list").Value())
+	})
+
+	t.Run("should return an error when the path is not valid", func(t *testing.T) {
+		for _, p := range []string{"", "friends.#(age>45", "friends.#()", `{a,b.#(c}`} {
+			_, err := Compile(p)
+			require.Error(t, err, p)
+			require.Panics(t, func() { MustCompile(p) }, p)
+		}
+	})
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("should accept valid paths", func(t *testing.T) {
+		for _, p := range []string{
+			"name.first",
+			`friends.#(nets.#(=="fb"))#.first`,
+			`friends.#(last="Murphy")#|#`,
+			`a\.b`,
+			`{name.first,"a.b":age}`,
+			`a.@flatten:{"deep":true}`,
+			"age==37.5.name",
+			"discount%",
+			"a<b.x>y",
+		} {
+			require.NoError(t, Validate(p), p)
+		}
+	})
+
+	t.Run("should describe the syntax errors", func(t *testing.T) {
+		for _, tc := range []struct {
+			path   string
+			offset int
+			reason string
+		}{
+			{"", 0, "empty path"},
+			{"friends.#(age>45", 9, `unclosed '('`},
+			{"friends.#(age>45))", 17, `unexpected ')'`},
+			{`{a,b.#(c}`, 8, `unexpected '}'`},
+			{`friends.#(first=="Dale)`, 17, "unterminated string literal"},
+			{`name\`, 4, "escape character at the end of the path"},
+			{"friends.#()", 10, "empty query"},
+			{"friends.#(age>)", 13, `missing value after operator ">"`},
+			{"age==", 3, `missing value after operator "=="`},
+			{"name.", 4, `dangling separator '.'`},
+			{"friends.#.|first", 10, "empty path component"},
+			{"name..first", 5, "empty path component"},
+			{`{name.first,age.}`, 15, `dangling separator '.'`},
+			{"{a,}", 3, "empty path component"},
+			{"[a,,b]", 3, "empty path component"},
+			{`{"k":}`, 5, "empty path component"},
+			{"friends.#(age>45)x", 8, `invalid query "#(age>45)x", expected it to end with ')' or ')#'`},
+		} {
+			err := Validate(tc.path)
+			var pathErr *PathError
+			require.True(t, errors.As(err, &pathErr), tc.path)
+			require.Equal(t, tc.path, pathErr.Path)
+			require.Equal(t, tc.offset, pathErr.Offset, tc.path)
+			require.Equal(t, tc.reason, pathErr.Reason, tc.path)
+		}
+	})
+
+	t.Run("should include the position in the error message", func(t *testing.T) {
+		require.EqualError(t, Validate("a.#(b>)"), `invalid path "a.#(b>)" at offset 5: missing value after operator ">"`)
+	})
+
+	t.Run("should return undefined values for invalid paths", func(t *testing.T) {
+		value := MustUnmarshalMap(friendsJSON)
+		require.False(t, value.Get("").Exists())
+		require.False(t, value.Get("friends.#(age>").Exists())
+		require.Equal(t, value, value.Set("name.", "x"))
+		require.Equal(t, "Tom", value.Get("name.first").String())
+	})
+}
@@ -0,0 +1,270 @@
+package jsonnav
+
+import (
+	"cmp"
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// queryOperators contains the comparison operators supported in conditions.
+// Two-character operators are listed first so they take precedence over their one-character prefixes.
+var queryOperators = []string{"==", "!=", "!%", "<=", ">=", "=", "<", ">", "%"}
+
+// keyOperators contains the operators supported in conditions on object keys like `name=Tom`, outside of queries.
+// Other operators are part of the key, so keys like `a<b` or `discount%` can be used without escaping.
+var keyOperators = []string{"==", "="}
+
+// condition represents a comparison expression like `age>=21`, where the left side is a path relative to the
+// evaluated value and the right side is a literal.
+type condition struct {
+	path     []component
+	operator string
+	literal  string
+	// text is the unquoted literal.
+	text string
+	// quoted determines whether the literal is a json string.
+	quoted bool
+}
+
+func newCondition(path []component, operator string, literal string) *condition {
+	text, quoted := unquoteLiteral(literal)
+	return &condition{
+		path:     path,
+		operator: operator,
+		literal:  literal,
+		text:     text,
+		quoted:   quoted,
+	}
+}
+
+// query represents the expression of a query like `#(age>45)#`.
+type query struct {
+	cond *condition
+	// exists determines whether the query checks the existence of the condition path, like `#(nets)`.
+	exists bool
+	// nonEmpty determines whether the existence check requires at least one match, like `#(nets.#(=="fb")#)`.
+	nonEmpty bool
+	// all determines whether all the matches should be returned.
+	all bool
+}
+
+// matches determines whether the array element satisfies the query.
+func (q *query) matches(value Value) bool {
+	if !q.exists {
+		return q.cond.matchesValue(value)
+	}
+
+	result := getComponents(value, q.cond.path)
+	if !result.Exists() {
+		return false
+	}
+	return !q.nonEmpty || !result.IsEmpty()
+}
+
+// indexOperator returns the index of the first of the operators that is not escaped or part of a string literal
+// or a nested query.
+func indexOperator(expr string, operators []string) (int, string) {
+	depth := 0
+	inString := false
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		if inString {
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '\\':
+			i++
+			continue
+		case '"':
+			inString = true
+			continue
+		case '(':
+			depth++
+			continue
+		case ')':
+			depth--
+			continue
+		}
+		if depth > 0 {
+			continue
+		}
+		for _, operator := range operators {
+			if strings.HasPrefix(expr[i:], operator) {
+				return i, operator
+			}
+		}
+	}
+	return -1, ""
+}
+
+// matchesValue determines whether the value at the condition path satisfies the condition.
+func (c *condition) matchesValue(value Value) bool {
+	value = getComponents(value, c.path)
+	return c.matches(value.Value(), value.Exists())
+}
+
+// matches determines whether the json value satisfies the condition according to gjson syntax.
+// Values are only ordered against literals of the same type: strings are compared lexicographically, numbers
+// numerically and false is lower than true. Pattern operators only match string values.
+func (c *condition) matches(value any, exists bool) bool {
+	if tilde, ok := toTildeBool(value, exists, c.literal); ok {
+		// Compare the converted value with true
+		return matchesOrder(c.operator, compareBool(tilde, true))
+	}
+	if !exists {
+		return false
+	}
+
+	if c.operator == "%" || c.operator == "!%" {
+		str, ok := value.(string)
+		if !ok {
+			return c.operator == "!%"
+		}
+		return matchPattern(str, c.text) == (c.operator == "%")
+	}
+
+	order, ok := compareToLiteral(value, c.text, c.quoted)
+	if !ok {
+		// Values of different types are never equal
+		return c.operator == "!="
+	}
+
+	return matchesOrder(c.operator, order)
+}
+
+// matchesOrder determines whether the result of a comparison satisfies the operator.
+func matchesOrder(operator string, order int) bool {
+	switch operator {
+	case "=", "==":
+		return order == 0
+	case "!=":
+		return order != 0
+	case "<":
+		return order < 0
+	case "<=":
+		return order <= 0
+	case ">":
+		return order > 0
+	case ">=":
+		return order >= 0
+	default:
+		return false
+	}
+}
+
+// compareToLiteral compares a json value with the text of a condition literal, returning -1, 0 or +1.
+// It returns false when the value can not be compared with the literal.
+func compareToLiteral(value any, text string, quoted bool) (int, bool) {
+	switch v := value.(type) {
+	case string:
+		return strings.Compare(v, text), true
+	case float64:
+		if quoted {
+			return 0, false
+		}
+		if expected, err := strconv.ParseFloat(text, 64); err == nil {
+			return cmp.Compare(v, expected), true
+		}
+	case json.Number:
+		if quoted {
+			return 0, false
+		}
+		// Large integers and decimals can't be represented as float64
+		n, ok := parseNumber(v.String())
+		expected, expectedOk := parseNumber(text)
+		if ok && expectedOk {
+			return n.Cmp(expected), true
+		}
+	case bool:
+		if quoted {
+			return 0, false
+		}
+		if expected, err := strconv.ParseBool(text); err == nil {
+			return compareBool(v, expected), true
+		}
+	case nil:
+		if !quoted && text == "null" {
+			return 0, true
+		}
+	}
+
+	return 0, false
+}
+
+// toTildeBool converts the value to a boolean when the literal is a tilde comparison (`~true`, `~false`, `~null`
+// or `~*`), following gjson conversion rules. It returns false when the literal is not a tilde comparison.
+func toTildeBool(value any, exists bool, literal string) (bool, bool) {
+	switch literal {
+	case "~true":
+		return exists && isTruthy(value), true
+	case "~false":
+		return !exists || !isTruthy(value), true
+	case "~null":
+		return !exists || value == nil, true
+	case "~*":
+		return exists, true
+	default:
+		return false, false
+	}
+}
+
+// isTruthy determines whether the json value represents a true-ish value: true, a non-zero number or a string
+// representation of true like "true", "t" or "1".
+func isTruthy(value any) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case json.Number:
+		n, ok := parseNumber(v.String())
+		return ok && n.Sign() != 0
+	case string:
+		b, _ := strconv.ParseBool(strings.ToLower(v))
+		return b
+	default:
+		return false
+	}
+}
+
+// numberPrecision is the precision in bits used to compare json numbers, enough to represent integers and decimals
+// of more than 150 digits.
+const numberPrecision = 512
+
+// parseNumber parses the text of a json number.
+func parseNumber(text string) (*big.Float, bool) {
+	n, _, err := big.ParseFloat(text, 10, numberPrecision, big.ToNearestEven)
+	return n, err == nil
+}
+
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// unquoteLiteral returns the text of a condition literal and whether it was a quoted json string.
+func unquoteLiteral(literal string) (string, bool) {
+	if len(literal) < 2 || literal[0] != '"' || literal[len(literal)-1] != '"' {
+		return literal, false
+	}
+
+	var text string
+	if err := json.Unmarshal([]byte(literal), &text); err != nil {
+		// Invalid escape sequences, use the raw content
+		return literal[1 : len(literal)-1], true
+	}
+	return text, true
+}
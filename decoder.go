@@ -0,0 +1,150 @@
+package jsonnav
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// Decoder reads a stream of concatenated or newline-delimited json documents, returning a Value per document.
+type Decoder struct {
+	dec     *json.Decoder
+	options decodeOptions
+}
+
+// NewDecoder returns a new Decoder that reads from r.
+func NewDecoder(r io.Reader, opts ...DecodeOption) *Decoder {
+	options := newDecodeOptions(opts)
+	return &Decoder{dec: newJSONDecoder(r, options), options: options}
+}
+
+// Decode reads the next json document from the stream and returns the Value.
+// It returns io.EOF when there are no more documents in the stream.
+func (d *Decoder) Decode() (Value, error) {
+	value, err := decodeRaw(d.dec, d.options)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPathValue(value)
+}
+
+// More reports whether there is another json document in the stream.
+func (d *Decoder) More() bool {
+	return d.dec.More()
+}
+
+// decodeOrdered decodes the next json value using ordered maps for objects.
+func decodeOrdered(dec *json.Decoder) (any, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		m := newOrderedMap()
+		for dec.More() {
+			token, err := nextToken(dec)
+			if err != nil {
+				return nil, err
+			}
+			key, ok := token.(string)
+			if !ok {
+				// The decoder only returns string keys for valid json
+				return nil, fmt.Errorf("unexpected object key %v", token)
+			}
+			value, err := decodeNestedOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			m.setRaw(key, value)
+		}
+		_, err = nextToken(dec)
+		return m, err
+	case json.Delim('['):
+		items := make([]any, 0)
+		for dec.More() {
+			value, err := decodeNestedOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, value)
+		}
+		_, err = nextToken(dec)
+		return items, err
+	default:
+		// Scalar value
+		return token, nil
+	}
+}
+
+// decodeNestedOrdered decodes a value within an object or array, where the end of the input is unexpected.
+func decodeNestedOrdered(dec *json.Decoder) (any, error) {
+	value, err := decodeOrdered(dec)
+	if errors.Is(err, io.EOF) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return value, err
+}
+
+// nextToken reads the next token within an object or array, where the end of the input is unexpected.
+func nextToken(dec *json.Decoder) (json.Token, error) {
+	token, err := dec.Token()
+	if errors.Is(err, io.EOF) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return token, err
+}
+
+// LineError describes an error parsing a line of a JSON Lines stream.
+type LineError struct {
+	// Line is the 1-based line number.
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeLines returns an iterator over the json documents of a JSON Lines (newline-delimited) stream.
+// Blank lines are skipped.
+//
+// Lines that can not be parsed yield a *LineError, and the iteration continues with the next line unless the
+// caller stops it. Errors reading from r are yielded as-is and end the iteration.
+func DecodeLines(r io.Reader, opts ...DecodeOption) iter.Seq2[Value, error] {
+	return func(yield func(Value, error) bool) {
+		reader := bufio.NewReader(r)
+		for line := 1; ; line++ {
+			data, readErr := reader.ReadBytes('\n')
+			if readErr != nil && !errors.Is(readErr, io.EOF) {
+				yield(nil, readErr)
+				return
+			}
+
+			if data = bytes.TrimSpace(data); len(data) > 0 {
+				value, err := UnmarshalBytes(data, opts...)
+				if err != nil {
+					err = &LineError{Line: line, Err: err}
+				}
+				if !yield(value, err) {
+					return
+				}
+			}
+
+			if readErr != nil {
+				// End of the stream
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package jsonnav
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecoder(t *testing.T) {
+	t.Run("should read concatenated and newline-delimited documents", func(t *testing.T) {
+		dec := NewDecoder(strings.NewReader(`{"a": 1}{"a": 2}
+			["b"]
+			"c" null
+		`))
+
+		var values []any
+		for dec.More() {
+			value, err := dec.Decode()
+			require.NoError(t, err)
+			values = append(values, value.Value())
+		}
+		require.Equal(t, []any{map[string]any{"a": 1.0}, map[string]any{"a": 2.0}, []any{"b"}, "c", nil}, values)
+
+		_, err := dec.Decode()
+		require.ErrorIs(t, err, io.EOF)
+	})
+
+	t.Run("should return an error when a document is not valid", func(t *testing.T) {
+		dec := NewDecoder(strings.NewReader(`{"a": 1} {"a": `))
+		value, err := dec.Decode()
+		require.NoError(t, err)
+		require.Equal(t, 1.0, value.Get("a").Float())
+
+		_, err = dec.Decode()
+		require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	})
+}
+
+func TestDecodeLines(t *testing.T) {
+	t.Run("should iterate over the documents", func(t *testing.T) {
+		r := strings.NewReader("{\"a\": 1}\n\n[2]\r\n  \"b\"  \nnull")
+		var values []any
+		for value, err := range DecodeLines(r) {
+			require.NoError(t, err)
+			values = append(values, value.Value())
+		}
+		require.Equal(t, []any{map[string]any{"a": 1.0}, []any{2.0}, "b", nil}, values)
+	})
+
+	t.Run("should include the line number in errors and continue", func(t *testing.T) {
+		r := strings.NewReader("{\"a\": 1}\n{\"a\": \n{\"a\": 3}\n")
+		var values []any
+		var errs []error
+		for value, err := range DecodeLines(r) {
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			values = append(values, value.Get("a").Value())
+		}
+		require.Equal(t, []any{1.0, 3.0}, values)
+		require.Len(t, errs, 1)
+
+		var lineErr *LineError
+		require.ErrorAs(t, errs[0], &lineErr)
+		require.Equal(t, 2, lineErr.Line)
+		require.ErrorContains(t, errs[0], "line 2: ")
+	})
+
+	t.Run("should stop when the caller stops", func(t *testing.T) {
+		count := 0
+		for range DecodeLines(strings.NewReader("1\n2\n3\n")) {
+			count++
+			break
+		}
+		require.Equal(t, 1, count)
+	})
+
+	t.Run("should yield read errors", func(t *testing.T) {
+		readErr := errors.New("test read error")
+		r := io.MultiReader(strings.NewReader("1\n"), &failingReader{err: readErr})
+		var errs []error
+		for _, err := range DecodeLines(r) {
+			errs = append(errs, err)
+		}
+		require.Equal(t, []error{nil, readErr}, errs)
+	})
+}
+
+type failingReader struct {
+	err error
+}
+
+func (r *failingReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
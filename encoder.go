@@ -0,0 +1,173 @@
+package jsonnav
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"iter"
+	"maps"
+	"slices"
+)
+
+// EncodeOption configures how Encode writes the json.
+type EncodeOption func(*encodeOptions)
+
+type encodeOptions struct {
+	prefix     string
+	indent     string
+	escapeHTML bool
+	sortKeys   bool
+}
+
+// WithIndent writes each element on a new line, indented by one copy of indent per nesting level.
+func WithIndent(indent string) EncodeOption {
+	return func(o *encodeOptions) {
+		o.indent = indent
+	}
+}
+
+// WithPrefix writes the prefix at the beginning of each new line, after the first one.
+func WithPrefix(prefix string) EncodeOption {
+	return func(o *encodeOptions) {
+		o.prefix = prefix
+	}
+}
+
+// WithEscapeHTML sets whether the characters <, > and & are escaped within json strings. Defaults to true.
+func WithEscapeHTML(escape bool) EncodeOption {
+	return func(o *encodeOptions) {
+		o.escapeHTML = escape
+	}
+}
+
+// WithSortedKeys writes the keys of all objects in sorted order, including ordered maps. By default, the keys of
+// ordered maps are written in order and the keys of other objects are written in sorted order, as with Marshal.
+func WithSortedKeys() EncodeOption {
+	return func(o *encodeOptions) {
+		o.sortKeys = true
+	}
+}
+
+// Encode writes the json encoding of the value to w, followed by a newline character.
+// The value is written as it's traversed, without building the whole json in memory. Values created with
+// UnmarshalLazy() are decoded, keeping the order of the source when they were not modified.
+func Encode(w io.Writer, value Value, opts ...EncodeOption) error {
+	e := &encoder{w: bufio.NewWriter(w), options: encodeOptions{escapeHTML: true}}
+	for _, opt := range opts {
+		opt(&e.options)
+	}
+	e.scalarEncoder = json.NewEncoder(&e.scalarBuf)
+	e.scalarEncoder.SetEscapeHTML(e.options.escapeHTML)
+
+	rawValue := toRawValue(value)
+	if lazy, ok := value.(*LazyValue); ok && !lazy.modified {
+		// The source was already validated
+		rawValue = must(unmarshalRaw(lazy.data, decodeOptions{useNumber: lazy.options.useNumber, preserveOrder: true}))
+	}
+	if err := e.encode(rawValue, 0); err != nil {
+		return err
+	}
+	e.w.WriteByte('\n')
+
+	// Errors writing to the bufio.Writer are sticky and returned by Flush
+	return e.w.Flush()
+}
+
+type encoder struct {
+	w             *bufio.Writer
+	options       encodeOptions
+	scalarBuf     bytes.Buffer
+	scalarEncoder *json.Encoder
+}
+
+func (e *encoder) encode(rawValue any, depth int) error {
+	switch v := rawValue.(type) {
+	case map[string]any:
+		return e.encodeMap(v, sortedKeys(v), depth)
+	case *Map:
+		if v.ordered && !e.options.sortKeys {
+			return e.encodeMap(v.m, slices.Values(v.keys), depth)
+		}
+		return e.encodeMap(v.m, sortedKeys(v.m), depth)
+	case []any:
+		return e.encodeSlice(v, depth)
+	default:
+		return e.encodeScalar(v)
+	}
+}
+
+func (e *encoder) encodeMap(m map[string]any, keys iter.Seq[string], depth int) error {
+	e.w.WriteByte('{')
+	first := true
+	for key := range keys {
+		if !first {
+			e.w.WriteByte(',')
+		}
+		first = false
+		e.newline(depth + 1)
+		if err := e.encodeScalar(key); err != nil {
+			return err
+		}
+		e.w.WriteByte(':')
+		if e.indenting() {
+			e.w.WriteByte(' ')
+		}
+		if err := e.encode(m[key], depth+1); err != nil {
+			return err
+		}
+	}
+	if !first {
+		e.newline(depth)
+	}
+	e.w.WriteByte('}')
+	return nil
+}
+
+func (e *encoder) encodeSlice(s []any, depth int) error {
+	e.w.WriteByte('[')
+	for i, item := range s {
+		if i > 0 {
+			e.w.WriteByte(',')
+		}
+		e.newline(depth + 1)
+		if err := e.encode(item, depth+1); err != nil {
+			return err
+		}
+	}
+	if len(s) > 0 {
+		e.newline(depth)
+	}
+	e.w.WriteByte(']')
+	return nil
+}
+
+// encodeScalar writes the value using the json encoder, without the trailing newline.
+func (e *encoder) encodeScalar(rawValue any) error {
+	e.scalarBuf.Reset()
+	if err := e.scalarEncoder.Encode(rawValue); err != nil {
+		return err
+	}
+	e.w.Write(bytes.TrimSuffix(e.scalarBuf.Bytes(), []byte{'\n'}))
+	return nil
+}
+
+// sortedKeys returns the keys of the map in sorted order.
+func sortedKeys(m map[string]any) iter.Seq[string] {
+	return slices.Values(slices.Sorted(maps.Keys(m)))
+}
+
+func (e *encoder) indenting() bool {
+	return e.options.prefix != "" || e.options.indent != ""
+}
+
+func (e *encoder) newline(depth int) {
+	if !e.indenting() {
+		return
+	}
+	e.w.WriteByte('\n')
+	e.w.WriteString(e.options.prefix)
+	for range depth {
+		e.w.WriteString(e.options.indent)
+	}
+}
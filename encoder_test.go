@@ -0,0 +1,103 @@
+package jsonnav
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode(t *testing.T) {
+	value := MustUnmarshalMap(friendsJSON)
+
+	t.Run("should write compact json", func(t *testing.T) {
+		var sb strings.Builder
+		require.NoError(t, Encode(&sb, value, WithSortedKeys()))
+		expected, err := json.Marshal(value.Value())
+		require.NoError(t, err)
+		require.Equal(t, string(expected)+"\n", sb.String())
+
+		// Keys are sorted by default, as with Marshal
+		sb.Reset()
+		require.NoError(t, Encode(&sb, value))
+		require.Equal(t, string(expected)+"\n", sb.String())
+
+		sb.Reset()
+		ordered := must(Unmarshal(`{"b": 1, "a": {"d": 2, "c": 3}}`, PreserveOrder()))
+		require.NoError(t, Encode(&sb, ordered))
+		require.NoError(t, Encode(&sb, ordered, WithSortedKeys()))
+		require.Equal(t, `{"b":1,"a":{"d":2,"c":3}}`+"\n"+`{"a":{"c":3,"d":2},"b":1}`+"\n", sb.String())
+	})
+
+	t.Run("should write indented json", func(t *testing.T) {
+		var sb strings.Builder
+		require.NoError(t, Encode(&sb, value, WithSortedKeys(), WithIndent("  "), WithPrefix("> ")))
+		expected, err := json.MarshalIndent(value.Value(), "> ", "  ")
+		require.NoError(t, err)
+		require.Equal(t, string(expected)+"\n", sb.String())
+
+		sb.Reset()
+		require.NoError(t, Encode(&sb, MustUnmarshalMap(`{"a": {}, "b": [], "c": [{}]}`), WithSortedKeys(),
+			WithIndent("\t")))
+		require.Equal(t, "{\n\t\"a\": {},\n\t\"b\": [],\n\t\"c\": [\n\t\t{}\n\t]\n}\n", sb.String())
+	})
+
+	t.Run("should write scalars and slices", func(t *testing.T) {
+		var sb strings.Builder
+		require.NoError(t, Encode(&sb, value.Get("children")))
+		require.NoError(t, Encode(&sb, value.Get("age")))
+		require.NoError(t, Encode(&sb, MustUnmarshalScalar("null")))
+		require.Equal(t, "[\"Sara\",\"Alex\",\"Jack\"]\n37\nnull\n", sb.String())
+	})
+
+	t.Run("should escape html by default", func(t *testing.T) {
+		v := From(map[string]any{"html": "<b>&</b>"})
+		var sb strings.Builder
+		require.NoError(t, Encode(&sb, v))
+		require.Equal(t, `{"html":"\u003cb\u003e\u0026\u003c/b\u003e"}`+"\n", sb.String())
+
+		sb.Reset()
+		require.NoError(t, Encode(&sb, v, WithEscapeHTML(false)))
+		require.Equal(t, `{"html":"<b>&</b>"}`+"\n", sb.String())
+	})
+
+	t.Run("should write lazy values with the options", func(t *testing.T) {
+		lazy, err := UnmarshalLazy([]byte(`{"b": [1, {"d": 2, "c": 3}], "a": "x"}`))
+		require.NoError(t, err)
+		var sb strings.Builder
+		require.NoError(t, Encode(&sb, lazy, WithIndent("  "), WithPrefix("> ")))
+		require.Equal(t,
+			"{\n>   \"b\": [\n>     1,\n>     {\n>       \"d\": 2,\n>       \"c\": 3\n>     }\n>   ],\n>   \"a\": \"x\"\n> }\n",
+			sb.String())
+
+		sb.Reset()
+		require.NoError(t, Encode(&sb, lazy, WithSortedKeys()))
+		require.Equal(t, `{"a":"x","b":[1,{"c":3,"d":2}]}`+"\n", sb.String())
+
+		sb.Reset()
+		require.NoError(t, Encode(&sb, From(map[string]any{"lazy": lazy.Get("b.1")}), WithIndent(" "),
+			WithSortedKeys()))
+		require.Equal(t, "{\n \"lazy\": {\n  \"c\": 3,\n  \"d\": 2\n }\n}\n", sb.String())
+	})
+
+	t.Run("should return write errors", func(t *testing.T) {
+		writeErr := errors.New("test write error")
+		require.ErrorIs(t, Encode(&failingWriter{err: writeErr}, value), writeErr)
+	})
+
+	t.Run("should return an error for values that can not be encoded", func(t *testing.T) {
+		v := FromJSONMap(map[string]any{"a": []any{func() {}}})
+		var sb strings.Builder
+		require.Error(t, Encode(&sb, v))
+	})
+}
+
+type failingWriter struct {
+	err error
+}
+
+func (w *failingWriter) Write([]byte) (int, error) {
+	return 0, w.err
+}
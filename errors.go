@@ -0,0 +1,17 @@
+package jsonnav
+
+import "errors"
+
+var (
+	// ErrNotFound is returned when the path does not exist within the value.
+	ErrNotFound = errors.New("not found")
+	// ErrTypeMismatch is returned when the path component can not be applied to the type of the value,
+	// for example, an object key on an array or any key on a scalar, or when the value is not of the expected type.
+	ErrTypeMismatch = errors.New("type mismatch")
+	// ErrIndexOutOfRange is returned when the array index is negative or greater than the length of the array.
+	ErrIndexOutOfRange = errors.New("index out of range")
+	// ErrOverflow is returned when the number can not be represented by the numeric type.
+	ErrOverflow = errors.New("number out of range")
+	// ErrNotInteger is returned when the number has a fractional part.
+	ErrNotInteger = errors.New("number is not an integer")
+)
@@ -0,0 +1,527 @@
+package jsonnav
+
+import (
+	"bytes"
+	"encoding/json"
+	"iter"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// LazyValue is a Value backed by the json source that is decoded on demand.
+//
+// Object keys, wildcard keys, array indexes, `#` and queries are resolved by scanning the source, returning values
+// backed by the source of the child nodes, so only the accessed subtrees are decoded. Other path expressions decode
+// the value.
+// Raw() returns the exact source text of the value until it or its parent is modified with Set() or Delete().
+//
+// The objects and arrays obtained from a LazyValue are attached to it, as they are for decoded values: changes made
+// on them are made through the parent, and when the parent is modified they reference the decoded value of the
+// parent.
+type LazyValue struct {
+	data    []byte
+	options decodeOptions
+	// parent is the value containing the source of the value at the path.
+	parent *LazyValue
+	path   []component
+	// mu guards the fields below.
+	mu       sync.Mutex
+	value    Value
+	modified bool
+	// children contains the objects and arrays obtained from the source by the address of their source.
+	children map[*byte]*LazyValue
+}
+
+var _ Value = (*LazyValue)(nil)
+
+// UnmarshalLazy validates the json and returns a Value backed by the data that is decoded on demand.
+// The data must not be modified afterwards.
+func UnmarshalLazy(data []byte, opts ...DecodeOption) (*LazyValue, error) {
+	if !json.Valid(data) {
+		// Use the json package to describe the syntax error
+		var value any
+		return nil, json.Unmarshal(data, &value)
+	}
+	return &LazyValue{data: bytes.TrimSpace(data), options: newDecodeOptions(opts)}, nil
+}
+
+// decoded returns the decoded value, decoding the source on first use.
+func (v *LazyValue) decoded() Value {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.value == nil {
+		// The source was already validated
+		v.value = mustToPathValue(must(unmarshalRaw(v.data, v.options)))
+	}
+	return v.value
+}
+
+// current returns the decoded value and true when the value was modified, directly or through its parent.
+func (v *LazyValue) current() (Value, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.value, v.modified
+}
+
+// attach makes the value and the children obtained from it reference the decoded value.
+// It's called before the parent is modified, so the path resolves to the value of the source.
+func (v *LazyValue) attach(value Value) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.value, v.modified = value, true
+	for _, child := range v.children {
+		child.attach(getComponents(value, child.path))
+	}
+	v.children = nil
+}
+
+// child returns a value backed by the source of a child node, at the path component within this value.
+// Objects and arrays are attached to this value, returning the same value for the same source.
+func (v *LazyValue) child(data []byte, c component) *LazyValue {
+	if data[0] != '{' && data[0] != '[' {
+		// Scalars are immutable
+		return &LazyValue{data: data, options: v.options}
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if result, ok := v.children[&data[0]]; ok {
+		return result
+	}
+	if v.children == nil {
+		v.children = make(map[*byte]*LazyValue)
+	}
+	result := &LazyValue{data: data, options: v.options, parent: v, path: []component{c}}
+	v.children[&data[0]] = result
+	return result
+}
+
+// Raw returns the source text of the value or the compact json encoding when it was modified.
+func (v *LazyValue) Raw() []byte {
+	if value, modified := v.current(); modified {
+		return rawJSON(value)
+	}
+	return v.data
+}
+
+// Clone returns a copy of the value backed by the same source, detached from the parent.
+// When the value was modified, the decoded value is copied.
+func (v *LazyValue) Clone() Value {
+	result := &LazyValue{data: v.data, options: v.options}
+	if value, modified := v.current(); modified {
+		result.value, result.modified = value.Clone(), true
+	}
+	return result
+}
+
+// Decode stores the value in the Go value pointed by target, decoding the source.
+func (v *LazyValue) Decode(target any) error {
+	return decodeValue(v, target)
+}
+
+// MarshalJSON returns the json encoding of the value.
+func (v *LazyValue) MarshalJSON() ([]byte, error) {
+	if value, modified := v.current(); modified {
+		return json.Marshal(toRawValue(value))
+	}
+	return v.data, nil
+}
+
+// Exists returns true for lazy values.
+func (v *LazyValue) Exists() bool {
+	return true
+}
+
+// IsEmpty returns true for null, empty strings, empty objects and empty arrays.
+func (v *LazyValue) IsEmpty() bool {
+	if value, modified := v.current(); modified {
+		return value.IsEmpty()
+	}
+	switch v.data[0] {
+	case '{', '[':
+		return skipSpace(v.data, 1) == len(v.data)-1
+	case '"':
+		return len(v.data) == len(`""`)
+	default:
+		return v.IsNull()
+	}
+}
+
+// IsNull returns true when the value is the json null.
+func (v *LazyValue) IsNull() bool {
+	return v.data[0] == 'n'
+}
+
+// IsArray returns true when the value is a json array.
+func (v *LazyValue) IsArray() bool {
+	return v.data[0] == '['
+}
+
+// IsObject returns true when the value is a json object.
+func (v *LazyValue) IsObject() bool {
+	return v.data[0] == '{'
+}
+
+// IsString returns true when the value is a json string.
+func (v *LazyValue) IsString() bool {
+	return v.data[0] == '"'
+}
+
+// IsFloat returns true when the value is a json number.
+func (v *LazyValue) IsFloat() bool {
+	return v.data[0] == '-' || (v.data[0] >= '0' && v.data[0] <= '9')
+}
+
+// IsBool returns true when the value is a json boolean.
+func (v *LazyValue) IsBool() bool {
+	return v.data[0] == 't' || v.data[0] == 'f'
+}
+
+// IsInt returns true when the value is a json number without a fractional part.
+func (v *LazyValue) IsInt() bool {
+	return v.IsFloat() && v.decoded().IsInt()
+}
+
+// Bool returns the boolean value, false for other types.
+func (v *LazyValue) Bool() bool {
+	return v.IsBool() && v.decoded().Bool()
+}
+
+// Float returns the float representation of numbers and numeric strings, 0 for other types.
+func (v *LazyValue) Float() float64 {
+	return v.decoded().Float()
+}
+
+// Int returns the integer representation of numbers and numeric strings, 0 for other types.
+func (v *LazyValue) Int() int64 {
+	return v.decoded().Int()
+}
+
+// Uint returns the unsigned integer representation of numbers and numeric strings, 0 for other types.
+func (v *LazyValue) Uint() uint64 {
+	return v.decoded().Uint()
+}
+
+// FloatE returns the float64 representation of numbers and numeric strings.
+func (v *LazyValue) FloatE() (float64, error) {
+	return v.decoded().FloatE()
+}
+
+// IntE returns the int64 representation of numbers and numeric strings.
+func (v *LazyValue) IntE() (int64, error) {
+	return v.decoded().IntE()
+}
+
+// UintE returns the uint64 representation of numbers and numeric strings.
+func (v *LazyValue) UintE() (uint64, error) {
+	return v.decoded().UintE()
+}
+
+// String returns the string representation of scalars, an empty string for objects and arrays.
+func (v *LazyValue) String() string {
+	if v.IsObject() || v.IsArray() {
+		return ""
+	}
+	return v.decoded().String()
+}
+
+// Value returns the decoded value.
+func (v *LazyValue) Value() any {
+	return v.decoded().Value()
+}
+
+// Get searches for the specified path.
+func (v *LazyValue) Get(path string) Value {
+	return getPath(v, path)
+}
+
+func (v *LazyValue) get(c *component, next []component) Value {
+	if value, modified := v.current(); modified {
+		return getComponent(value, c, next)
+	}
+
+	switch {
+	case v.IsObject():
+		return v.getKey(c, next)
+	case v.IsArray() && c.kind == keyComponent && c.index != -1:
+		for i, element := range v.elements() {
+			if i == c.index {
+				return getComponents(element, next)
+			}
+		}
+		return undefinedScalar
+	case v.IsArray() && c.kind == countComponent:
+		count := 0
+		for range v.elements() {
+			count++
+		}
+		return getComponents(&scalar{v: float64(count)}, next)
+	case v.IsArray():
+		// Evaluate queries and mapped paths on the elements backed by the source
+		return v.Array().get(c, next)
+	default:
+		return getComponent(v.decoded(), c, next)
+	}
+}
+
+// getKey evaluates the component on the object source, following the same rules as Map.
+func (v *LazyValue) getKey(c *component, next []component) Value {
+	switch c.kind {
+	case conditionComponent:
+		child, ok := v.lookup(c.key)
+		var rawValue any
+		if ok {
+			rawValue = toRawValue(child)
+		}
+		if !c.cond.matches(rawValue, ok) {
+			return undefinedScalar
+		}
+		return getComponents(v, next)
+	case keyComponent, countComponent, mapComponent:
+		key := c.key
+		if c.wildcard {
+			keys := v.matchingKeys(c.key)
+			if len(keys) == 0 {
+				return undefinedScalar
+			}
+			key = keys[0]
+		}
+		child, ok := v.lookup(key)
+		if !ok {
+			return undefinedScalar
+		}
+		return getComponents(getComponents(child, c.sub), next)
+	default:
+		// Queries are only supported on arrays
+		return undefinedScalar
+	}
+}
+
+// matchingKeys returns the keys of the object source matching the wildcard pattern, in the order of Map.Keys().
+func (v *LazyValue) matchingKeys(pattern string) []string {
+	var keys []string
+	for rawKey := range objectEntries(v.data) {
+		if key := unquoteRawKey(rawKey); matchPattern(key, pattern) && !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	if !v.options.preserveOrder {
+		slices.Sort(keys)
+	}
+	return keys
+}
+
+// lookup returns the value of the key within the object source.
+// When the key is duplicated, the last one is used as when decoding.
+func (v *LazyValue) lookup(key string) (*LazyValue, bool) {
+	var result []byte
+	for rawKey, rawValue := range objectEntries(v.data) {
+		if bytes.IndexByte(rawKey, '\\') == -1 {
+			if string(rawKey[1:len(rawKey)-1]) == key {
+				result = rawValue
+			}
+		} else if unquoteRawKey(rawKey) == key {
+			result = rawValue
+		}
+	}
+
+	if result == nil {
+		return nil, false
+	}
+	return v.child(result, keyPathComponent(key)), true
+}
+
+// elements returns an iterator over the elements of the array source.
+func (v *LazyValue) elements() iter.Seq2[int, *LazyValue] {
+	return func(yield func(int, *LazyValue) bool) {
+		i := 0
+		for rawValue := range arrayElements(v.data) {
+			if !yield(i, v.child(rawValue, indexPathComponent(i))) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// Set sets the value at the specified path, decoding the value.
+func (v *LazyValue) Set(path string, rawValue any) Value {
+	return setPath(v, path, rawValue)
+}
+
+// SetRaw parses the json text with the decode options of the value and sets it at the specified path.
+func (v *LazyValue) SetRaw(path string, jsonText string) (Value, error) {
+	return setRawPath(v, path, jsonText, v.options)
+}
+
+func (v *LazyValue) set(components []component, rawValue any) Value {
+	if !v.IsObject() && !v.IsArray() {
+		// Scalar values can't be set by path: noop
+		return v
+	}
+	value, modified := v.current()
+	if !modified && v.parent != nil {
+		// Set the value through the parent, which attaches this value to the decoded value of the parent
+		v.parent.set(slices.Concat(v.path, components), rawValue)
+		return v
+	}
+	if !modified {
+		value = v.decoded()
+		v.attach(value)
+	}
+
+	result := setComponents(value, components, rawValue)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.value = result
+	return v
+}
+
+// Delete removes the value at the specified path, decoding the value.
+func (v *LazyValue) Delete(path string) Value {
+	return v.Set(path, deleteValue)
+}
+
+// Array returns the elements of the array backed by their source or the decoded elements when it was modified.
+// It returns an empty slice for null values and objects, and a slice containing the value for other scalars.
+func (v *LazyValue) Array() Slice {
+	if value, modified := v.current(); modified {
+		return value.Array()
+	}
+	switch {
+	case v.IsArray():
+		result := Slice{}
+		for _, element := range v.elements() {
+			result = append(result, element)
+		}
+		return result
+	case v.IsNull(), v.IsObject():
+		return Slice{}
+	default:
+		return Slice{v}
+	}
+}
+
+// Map returns the values of the object backed by their source or the decoded values when it was modified, an empty
+// map for other types.
+func (v *LazyValue) Map() map[string]Value {
+	if value, modified := v.current(); modified {
+		return value.Map()
+	}
+	result := map[string]Value{}
+	if v.IsObject() {
+		for rawKey, rawValue := range objectEntries(v.data) {
+			key := unquoteRawKey(rawKey)
+			result[key] = v.child(rawValue, keyPathComponent(key))
+		}
+	}
+	return result
+}
+
+// keyPathComponent returns the path component of an object key.
+func keyPathComponent(key string) component {
+	return component{kind: keyComponent, path: Escape(key), key: key, index: -1}
+}
+
+// indexPathComponent returns the path component of an array index.
+func indexPathComponent(index int) component {
+	key := strconv.Itoa(index)
+	return component{kind: keyComponent, path: key, key: key, index: index}
+}
+
+// skipSpace returns the index of the first character from i that is not json whitespace.
+func skipSpace(data []byte, i int) int {
+	for i < len(data) && (data[i] == ' ' || data[i] == '\t' || data[i] == '\n' || data[i] == '\r') {
+		i++
+	}
+	return i
+}
+
+// valueEnd returns the index after the end of the valid json value starting at i.
+func valueEnd(data []byte, i int) int {
+	switch data[i] {
+	case '"':
+		return stringEnd(data, i)
+	case '{', '[':
+		depth := 0
+		for ; i < len(data); i++ {
+			switch data[i] {
+			case '"':
+				i = stringEnd(data, i) - 1
+			case '{', '[':
+				depth++
+			case '}', ']':
+				if depth--; depth == 0 {
+					return i + 1
+				}
+			}
+		}
+		return i
+	default:
+		// Number or literal
+		for i < len(data) && strings.IndexByte(",}] \t\n\r", data[i]) == -1 {
+			i++
+		}
+		return i
+	}
+}
+
+// stringEnd returns the index after the closing quote of the json string starting at i.
+func stringEnd(data []byte, i int) int {
+	for i++; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return i
+}
+
+// objectEntries returns an iterator over the quoted keys and the values of a valid json object source.
+func objectEntries(data []byte) iter.Seq2[[]byte, []byte] {
+	return func(yield func([]byte, []byte) bool) {
+		i := skipSpace(data, 1)
+		for i < len(data) && data[i] == '"' {
+			keyEnd := stringEnd(data, i)
+			// Skip the colon
+			start := skipSpace(data, skipSpace(data, keyEnd)+1)
+			end := valueEnd(data, start)
+			if !yield(data[i:keyEnd], data[start:end]) {
+				return
+			}
+			if i = skipSpace(data, end); data[i] == ',' {
+				i = skipSpace(data, i+1)
+			}
+		}
+	}
+}
+
+// arrayElements returns an iterator over the elements of a valid json array source.
+func arrayElements(data []byte) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		i := skipSpace(data, 1)
+		for i < len(data) && data[i] != ']' {
+			end := valueEnd(data, i)
+			if !yield(data[i:end]) {
+				return
+			}
+			if i = skipSpace(data, end); data[i] == ',' {
+				i = skipSpace(data, i+1)
+			}
+		}
+	}
+}
+
+// unquoteRawKey returns the string of a valid quoted json key.
+func unquoteRawKey(rawKey []byte) string {
+	if bytes.IndexByte(rawKey, '\\') == -1 {
+		return string(rawKey[1 : len(rawKey)-1])
+	}
+	var key string
+	_ = json.Unmarshal(rawKey, &key)
+	return key
+}
@@ -0,0 +1,202 @@
+package jsonnav
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalLazy(t *testing.T) {
+	const doc = ` {"name": {"first": "Tom", "last":"Anderson"}, "age": 37,
+		"children": [ "Sara", "Alex",  "Jack" ], "k\"ey": [1, {"a" : [true, null]}], "dup": 1, "dup": 2,
+		"friends": [{"first": "Dale", "age": 44}, {"first": "Roger", "age": 68}]} `
+
+	t.Run("should get values backed by the source", func(t *testing.T) {
+		value, err := UnmarshalLazy([]byte(doc))
+		require.NoError(t, err)
+		require.Equal(t, "Tom", value.Get("name.first").String())
+		require.Equal(t, `{"first": "Tom", "last":"Anderson"}`, string(value.Get("name").Raw()))
+		require.Equal(t, `[ "Sara", "Alex",  "Jack" ]`, string(value.Get("children").Raw()))
+		require.Equal(t, `"Alex"`, string(value.Get("children.1").Raw()))
+		require.Equal(t, `[true, null]`, string(value.Get(`k\"ey.1.a`).Raw()))
+		require.Equal(t, 37.0, value.Get("age").Float())
+		require.Equal(t, int64(3), value.Get("children.#").Int())
+		require.Equal(t, 2.0, value.Get("dup").Float())
+		require.False(t, value.Get("children.3").Exists())
+		require.False(t, value.Get("name.middle").Exists())
+		require.False(t, value.Get("age.a").Exists())
+		require.Nil(t, value.Get("missing").Raw())
+
+		_, isLazy := value.Get("name").(*LazyValue)
+		require.True(t, isLazy)
+	})
+
+	t.Run("should return the source of the values matched by queries and wildcards", func(t *testing.T) {
+		value, err := UnmarshalLazy([]byte(doc))
+		require.NoError(t, err)
+		require.Equal(t, `{"first": "Roger", "age": 68}`, string(value.Get("friends.#(age>45)").Raw()))
+		require.Equal(t, `{"first": "Tom", "last":"Anderson"}`, string(value.Get("na*").Raw()))
+		require.Equal(t, `{"a" : [true, null]}`, string(value.Get(`k\"ey.#(a)`).Raw()))
+		require.Equal(t, `{"first": "Dale", "age": 44}`, string(value.Get("friends.#(age<45)#").Array()[0].Raw()))
+		require.Equal(t, `[true, null]`, string(value.Get(`k\"ey.#.a`).Array()[0].Raw()))
+
+		// Computed results are encoded
+		require.Equal(t, `["Dale","Roger"]`, string(value.Get("friends.#.first").Raw()))
+		require.Equal(t, `["Jack","Alex","Sara"]`, string(value.Get("children|@reverse").Raw()))
+	})
+
+	t.Run("should support the same syntax as decoded values", func(t *testing.T) {
+		value, err := UnmarshalLazy([]byte(doc))
+		require.NoError(t, err)
+		decoded, err := Unmarshal(doc)
+		require.NoError(t, err)
+		for _, p := range []string{
+			"friends.#(age>45).first",
+			"friends.#.first",
+			"children.@reverse",
+			"na*.last",
+			`{name.first,"count":children.#}`,
+			"age==37.name.last",
+			"friends.1|first",
+		} {
+			require.Equal(t, decoded.Get(p).Value(), value.Get(p).Value(), p)
+		}
+		require.Equal(t, decoded.Value(), value.Value())
+	})
+
+	t.Run("should check types without decoding", func(t *testing.T) {
+		value, err := UnmarshalLazy([]byte(doc))
+		require.NoError(t, err)
+		require.True(t, value.IsObject())
+		require.True(t, value.Get("children").IsArray())
+		require.True(t, value.Get("name.first").IsString())
+		require.True(t, value.Get("age").IsFloat())
+		require.True(t, value.Get("age").IsInt())
+		require.True(t, value.Get(`k\"ey.1.a.0`).IsBool())
+		require.True(t, value.Get(`k\"ey.1.a.0`).Bool())
+		require.True(t, value.Get(`k\"ey.1.a.1`).IsNull())
+		require.True(t, value.Get(`k\"ey.1.a.1`).IsEmpty())
+		require.False(t, value.Get("children").IsEmpty())
+		require.Equal(t, "", value.Get("name").String())
+	})
+
+	t.Run("should iterate over arrays and objects", func(t *testing.T) {
+		value, err := UnmarshalLazy([]byte(doc))
+		require.NoError(t, err)
+		children := value.Get("children").Array()
+		require.Len(t, children, 3)
+		require.Equal(t, `"Jack"`, string(children[2].Raw()))
+
+		name := value.Get("name").Map()
+		require.Len(t, name, 2)
+		require.Equal(t, `"Anderson"`, string(name["last"].Raw()))
+		require.Equal(t, Slice{value.Get("age")}, value.Get("age").Array())
+	})
+
+	t.Run("should decode when modified", func(t *testing.T) {
+		value, err := UnmarshalLazy([]byte(doc))
+		require.NoError(t, err)
+		value.Set("name.middle", "J")
+		value.Delete("friends")
+		value.Delete("k\\\"ey")
+		value.Delete("dup")
+		require.Equal(t, "J", value.Get("name.middle").String())
+		require.Equal(t,
+			`{"age":37,"children":["Sara","Alex","Jack"],"name":{"first":"Tom","last":"Anderson","middle":"J"}}`,
+			string(value.Raw()))
+	})
+
+	t.Run("should reflect the changes made on the values obtained", func(t *testing.T) {
+		value, err := UnmarshalLazy([]byte(`{"x": {"a": 1}, "list": [{"b": 1}], "y": 1.50}`))
+		require.NoError(t, err)
+		x := value.Get("x")
+		x.Set("a", 2)
+		value.Get("list.#(b==1)").Set("b", 2)
+		require.Equal(t, `{"list":[{"b":2}],"x":{"a":2},"y":1.5}`, string(value.Raw()))
+		require.Equal(t, `{"a":2}`, string(x.Raw()))
+
+		// Values obtained before the parent was modified reference the decoded value
+		list := value.Get("list")
+		value.Set("z", 1)
+		x.Set("c", 3)
+		value.Get("list").Array()[0].Set("b", 3)
+		value.Map()["x"].Set("a", 3)
+		list.Set("1", 4)
+		require.Equal(t, 3.0, value.Get("x.a").Float())
+		require.Equal(t, `{"a":3,"c":3}`, string(x.Raw()))
+		require.Equal(t, `{"list":[{"b":3},4],"x":{"a":3,"c":3},"y":1.5,"z":1}`, string(value.Raw()))
+
+		// Values removed from the parent are detached
+		value.Delete("x")
+		x.Set("d", 4)
+		require.False(t, value.Get("x").Exists())
+		require.Equal(t, `{"a":3,"c":3,"d":4}`, string(x.Raw()))
+	})
+
+	t.Run("should reflect the changes made on the parent", func(t *testing.T) {
+		value, err := UnmarshalLazy([]byte(`{"a": [1, 2], "b": {"c": 1}}`))
+		require.NoError(t, err)
+		a := value.Get("a")
+		b := value.Get("b")
+		value.Set("a.2", 3)
+		value.Set("b", "replaced")
+		require.Equal(t, `[1,2,3]`, string(a.Raw()))
+		require.Equal(t, `{"c":1}`, string(b.Raw()))
+
+		b.Set("c", 2)
+		require.Equal(t, "replaced", value.Get("b").String())
+		require.Equal(t, `{"c":2}`, string(b.Raw()))
+
+		// Values obtained before the parent is modified keep referencing the same value
+		value, err = UnmarshalLazy([]byte(`{"list": [{"id": 1}, {"id": 2}]}`))
+		require.NoError(t, err)
+		first := value.Get("list.0")
+		value.Get("list").Delete("0")
+		first.Set("id", 10)
+		require.Equal(t, `{"list":[{"id":2}]}`, string(value.Raw()))
+		require.Equal(t, `{"id":10}`, string(first.Raw()))
+
+		clone := value.Get("list").Clone()
+		clone.Set("0", 0)
+		require.Equal(t, `[{"id":2}]`, string(value.Get("list").Raw()))
+	})
+
+	t.Run("should marshal the source", func(t *testing.T) {
+		value, err := UnmarshalLazy([]byte(`{"b": [1, 2], "a": {"c" : 1}}`))
+		require.NoError(t, err)
+		str, err := Marshal(value)
+		require.NoError(t, err)
+		require.Equal(t, `{"b":[1,2],"a":{"c":1}}`, str)
+
+		m := MustUnmarshalMap(`{"x": 1}`)
+		m.Set("lazy", value.Get("a"))
+		str, err = Marshal(m)
+		require.NoError(t, err)
+		require.Equal(t, `{"lazy":{"c":1},"x":1}`, str)
+	})
+
+	t.Run("should return an error for invalid json", func(t *testing.T) {
+		_, err := UnmarshalLazy([]byte(`{"a": `))
+		require.Error(t, err)
+	})
+
+	t.Run("should support json.RawMessage", func(t *testing.T) {
+		value := FromAny(map[string]any{"payload": json.RawMessage(`{"id": 1, "tags": ["a"]}`)})
+		require.Equal(t, "a", value.Get("payload.tags.0").String())
+		require.Equal(t, `{"id":1,"tags":["a"]}`, string(value.Get("payload").Raw()))
+		require.Equal(t, map[string]any{"id": 1.0, "tags": []any{"a"}}, value.Get("payload").Value())
+
+		str, err := Marshal(value)
+		require.NoError(t, err)
+		require.Equal(t, `{"payload":{"id":1,"tags":["a"]}}`, str)
+	})
+}
+
+func TestRaw(t *testing.T) {
+	value := MustUnmarshalMap(friendsJSON)
+	require.Equal(t, `{"first":"Tom","last":"Anderson"}`, string(value.Get("name").Raw()))
+	require.Equal(t, `["Sara","Alex","Jack"]`, string(value.Get("children").Raw()))
+	require.Equal(t, `37`, string(value.Get("age").Raw()))
+	require.Nil(t, value.Get("missing").Raw())
+}
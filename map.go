@@ -1,13 +1,35 @@
 package jsonnav
 
 import (
-	"strconv"
-	"strings"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"maps"
+	"reflect"
+	"slices"
 )
 
 // Map represents a JSON object.
+//
+// Ordered maps, decoded using PreserveOrder(), keep the keys in source and insertion order.
 type Map struct {
 	m map[string]any
+	// keys contains the keys in order when the map is ordered.
+	keys    []string
+	ordered bool
+	// refs contains the arrays obtained from the map and its nested objects
+	refs *references
+}
+
+// newMap returns a map for the Go map.
+func newMap(m map[string]any) *Map {
+	return &Map{m: m, refs: &references{}}
+}
+
+// newOrderedMap returns an empty map that keeps the keys in insertion order.
+func newOrderedMap() *Map {
+	return &Map{m: make(map[string]any), ordered: true, refs: &references{}}
 }
 
 // Exists returns true if the value is defined.
@@ -50,7 +72,7 @@ func (*Map) IsBool() bool {
 	return false
 }
 
-// IsInt returns false for maps.
+// Bool returns false for maps.
 func (m *Map) Bool() bool {
 	return false
 }
@@ -65,87 +87,196 @@ func (m *Map) Int() int64 {
 	return 0
 }
 
+// Uint returns 0 for maps.
+func (m *Map) Uint() uint64 {
+	return 0
+}
+
+// IsInt returns false for maps.
+func (m *Map) IsInt() bool {
+	return false
+}
+
+// FloatE returns an ErrTypeMismatch error for maps.
+func (m *Map) FloatE() (float64, error) {
+	return 0, fmt.Errorf("%w: an object is not a number", ErrTypeMismatch)
+}
+
+// IntE returns an ErrTypeMismatch error for maps.
+func (m *Map) IntE() (int64, error) {
+	return 0, fmt.Errorf("%w: an object is not a number", ErrTypeMismatch)
+}
+
+// UintE returns an ErrTypeMismatch error for maps.
+func (m *Map) UintE() (uint64, error) {
+	return 0, fmt.Errorf("%w: an object is not a number", ErrTypeMismatch)
+}
+
 // String returns an empty string for maps.
 func (m *Map) String() string {
 	return ""
 }
 
 // Value returns the underlying map.
+// For ordered maps, it returns a copy as Go maps don't keep the order of the keys.
 func (m *Map) Value() any {
+	if m.ordered {
+		return plainValue(m)
+	}
 	return m.m
 }
 
+// Decode stores the map in the Go value pointed by target.
+func (m *Map) Decode(target any) error {
+	return decodeValue(m, target)
+}
+
+// Clone returns a deep copy of the map.
+func (m *Map) Clone() Value {
+	return cloneMap(m)
+}
+
+// Raw returns the compact json encoding of the map.
+func (m *Map) Raw() []byte {
+	return rawJSON(m)
+}
+
+// Keys returns the keys of the map in order for ordered maps, otherwise in sorted order.
+func (m *Map) Keys() []string {
+	if m.ordered {
+		return slices.Clone(m.keys)
+	}
+	return slices.Sorted(maps.Keys(m.m))
+}
+
+// All returns an iterator over the keys and values of the map, in the same order as Keys().
+func (m *Map) All() iter.Seq2[string, Value] {
+	return func(yield func(string, Value) bool) {
+		for _, key := range m.Keys() {
+			if !yield(key, m.child(key, m.m[key])) {
+				return
+			}
+		}
+	}
+}
+
 // Get searches json for the specified path.
 func (m *Map) Get(path string) Value {
-	if len(path) == 0 {
-		panic("invalid zero length")
-	}
-	key, remainingPath, _ := strings.Cut(path, ".")
-	equalityIndex := strings.Index(key, "=")
-	if equalityIndex != -1 {
-		// Only string conditions are supported
-		condition := key[equalityIndex+1:]
-		key = key[:equalityIndex]
+	return getPath(m, path)
+}
 
-		if !areEqualFromCondition(m.m[key], condition) {
+func (m *Map) get(c *component, next []component) Value {
+	switch c.kind {
+	case conditionComponent:
+		rawValue, ok := m.m[c.key]
+		if !c.cond.matches(rawValue, ok) {
 			return undefinedScalar
 		}
 
-		// Condition for map matched, return itself
-		if remainingPath == "" {
-			return m
+		// Condition for map matched, continue with itself
+		return getComponents(m, next)
+	case keyComponent, countComponent, mapComponent:
+		key, ok := m.lookup(c)
+		if !ok {
+			return undefinedScalar
 		}
-		return m.Get(remainingPath)
+		return getComponents(getComponents(m.child(key, m.m[key]), c.sub), next)
+	default:
+		// Queries are only supported on arrays
+		return undefinedScalar
 	}
+}
 
-	var value Value
-	if rawValue, ok := m.m[key]; ok {
-		value = mustToPathValue(rawValue)
-	} else {
-		value = undefinedScalar
+// lookup returns the existing key for the key of the component.
+// When the key contains wildcards, it returns the first matching key in the order of Keys().
+func (m *Map) lookup(c *component) (string, bool) {
+	if !c.wildcard {
+		_, ok := m.m[c.key]
+		return c.key, ok
 	}
 
-	if remainingPath == "" {
-		return value
+	keys := m.matchingKeys(c.key)
+	if len(keys) == 0 {
+		return "", false
 	}
-
-	return value.Get(remainingPath)
+	return keys[0], true
 }
 
-// areEqualFromCondition determines whether 2 values are equal according to gjson syntax.
-func areEqualFromCondition(value any, expected string) bool {
-	if value == expected {
-		return true
+// child returns the Value of the key, referencing its location for arrays.
+func (m *Map) child(key string, rawValue any) Value {
+	switch v := rawValue.(type) {
+	case []any:
+		slot := arraySlot{parent: reflect.ValueOf(m.m).UnsafePointer(), key: key}
+		return m.refs.array(slot, v, &keyLocation{m: m, key: key})
+	case map[string]any:
+		return &Map{m: v, refs: m.refs}
+	default:
+		return mustToPathValue(rawValue)
 	}
+}
+
+// matchingKeys returns the keys matching the wildcard pattern in the order of Keys().
+func (m *Map) matchingKeys(pattern string) []string {
+	return slices.DeleteFunc(m.Keys(), func(key string) bool {
+		return !matchPattern(key, pattern)
+	})
+}
+
+// Set updates the value at the specified path.
+// When a path component contains wildcards, the value is set in all the matching keys.
+func (m *Map) Set(path string, rawValue any) Value {
+	return setPath(m, path, rawValue)
+}
 
-	switch value.(type) {
-	case bool:
-		if typedExpected, err := strconv.ParseBool(expected); err == nil && value == typedExpected {
-			return true
+// SetRaw parses the json text and sets it at the specified path, preserving the order of the keys of ordered maps.
+func (m *Map) SetRaw(path string, jsonText string) (Value, error) {
+	return setRawPath(m, path, jsonText, decodeOptions{preserveOrder: m.ordered})
+}
+
+func (m *Map) set(components []component, rawValue any) Value {
+	c, next := &components[0], components[0].following(components[1:])
+	switch c.kind {
+	case keyComponent, countComponent, mapComponent:
+		if !c.wildcard {
+			m.setKey(c.key, next, rawValue)
+			break
 		}
-	case float64:
-		if typedExpected, err := strconv.ParseFloat(expected, 64); err == nil && value == typedExpected {
-			return true
+		for _, key := range m.matchingKeys(c.key) {
+			m.setKey(key, next, rawValue)
 		}
+	default:
+		// Only keys are supported: noop
 	}
-
-	return false
+	return m
 }
 
-// Set updates the value at the specified path.
-func (m *Map) Set(path string, rawValue any) Value {
-	key, remainingPath, _ := strings.Cut(path, ".")
-	if remainingPath == "" {
+func (m *Map) setKey(key string, next []component, rawValue any) {
+	if len(next) == 0 {
 		m.setLeaf(key, rawValue)
-		return m
+		return
 	}
 	if _, ok := m.m[key]; !ok {
 		// Insert a branch
-		m.m[key] = createRawChild(remainingPath)
+		m.setRaw(key, createRawChild(&next[0], m.ordered))
 	}
 
-	m.m[key] = mustToPathValue(m.m[key]).Set(remainingPath, rawValue).Value()
-	return m
+	m.m[key] = toRawValue(setComponents(m.child(key, m.m[key]), next, rawValue))
+}
+
+// setRaw sets the raw value of the key, appending the key when the map is ordered.
+func (m *Map) setRaw(key string, rawValue any) {
+	if _, ok := m.m[key]; !ok && m.ordered {
+		m.keys = append(m.keys, key)
+	}
+	m.m[key] = rawValue
+}
+
+// deleteKey removes the key, keeping the order of the remaining keys.
+func (m *Map) deleteKey(key string) {
+	if _, ok := m.m[key]; ok && m.ordered {
+		m.keys = slices.DeleteFunc(m.keys, func(k string) bool { return k == key })
+	}
+	delete(m.m, key)
 }
 
 // Delete removes the value at the specified path.
@@ -153,33 +284,126 @@ func (m *Map) Delete(path string) Value {
 	return m.Set(path, deleteValue)
 }
 
-func createRawChild(remainingPath string) any {
-	nextKey, _, _ := strings.Cut(remainingPath, ".")
-	childIsSlice := false
-	if _, err := strconv.Atoi(nextKey); err == nil {
-		childIsSlice = true
-	}
-	if childIsSlice {
+func createRawChild(next *component, ordered bool) any {
+	if next.kind == keyComponent && next.index != -1 {
 		return []any{}
 	}
+	if ordered {
+		return newOrderedMap()
+	}
 	return make(map[string]any)
 }
 
 func (m *Map) setLeaf(key string, rawValue any) {
 	if rawValue == deleteValue {
-		delete(m.m, key)
+		m.deleteKey(key)
 		return
 	}
-	m.m[key] = toJSONValue(rawValue)
+	m.setRaw(key, rawValue)
 }
 
-func toJSONValue(rawValue any) any {
-	if intValue, ok := rawValue.(int); ok {
-		// We use ints and float64 in an indistinctive way across our codebase
-		// Only float64 is valid json numbers
-		return float64(intValue)
+// toRawValue returns the raw json value of the Value to be stored within a map or array, keeping ordered maps.
+func toRawValue(value Value) any {
+	switch v := value.(type) {
+	case *Map:
+		if v.ordered {
+			return v
+		}
+		return v.m
+	case *array:
+		return v.items()
+	case Slice:
+		return v.rawItems()
+	case *LazyValue:
+		return toRawValue(v.decoded())
+	default:
+		return value.Value()
+	}
+}
+
+// cloneRaw returns a deep copy of the raw json value.
+func cloneRaw(rawValue any) any {
+	switch v := rawValue.(type) {
+	case map[string]any:
+		return cloneObject(v)
+	case *Map:
+		return cloneMap(v)
+	case []any:
+		return cloneItems(v)
+	default:
+		// Scalars are not modified
+		return v
+	}
+}
+
+func cloneMap(m *Map) *Map {
+	return &Map{m: cloneObject(m.m), keys: slices.Clone(m.keys), ordered: m.ordered, refs: &references{}}
+}
+
+func cloneObject(m map[string]any) map[string]any {
+	result := make(map[string]any, len(m))
+	for key, item := range m {
+		result[key] = cloneRaw(item)
+	}
+	return result
+}
+
+func cloneItems(items []any) []any {
+	if items == nil {
+		return nil
+	}
+	result := make([]any, len(items))
+	for i, item := range items {
+		result[i] = cloneRaw(item)
+	}
+	return result
+}
+
+// plainValue returns a copy of the raw json value where ordered maps are converted to Go maps.
+func plainValue(rawValue any) any {
+	switch v := rawValue.(type) {
+	case *Map:
+		result := make(map[string]any, len(v.m))
+		for key, child := range v.m {
+			result[key] = plainValue(child)
+		}
+		return result
+	case []any:
+		items := make([]any, 0, len(v))
+		for _, item := range v {
+			items = append(items, plainValue(item))
+		}
+		return items
+	default:
+		return rawValue
+	}
+}
+
+// MarshalJSON returns the json encoding of the map, keeping the order of the keys for ordered maps.
+func (m *Map) MarshalJSON() ([]byte, error) {
+	if !m.ordered {
+		return json.Marshal(m.m)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		blob, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(blob)
+		buf.WriteByte(':')
+		if blob, err = json.Marshal(m.m[key]); err != nil {
+			return nil, err
+		}
+		buf.Write(blob)
 	}
-	return rawValue
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
 }
 
 // Array returns an empty slice for maps.
@@ -191,7 +415,7 @@ func (m *Map) Array() Slice {
 func (m *Map) Map() map[string]Value {
 	newMap := make(map[string]Value, len(m.m))
 	for k, v := range m.m {
-		newMap[k] = mustToPathValue(v)
+		newMap[k] = m.child(k, v)
 	}
 
 	return newMap
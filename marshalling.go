@@ -1,32 +1,156 @@
 package jsonnav
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 )
 
+// DecodeOption configures how json is decoded.
+type DecodeOption func(*decodeOptions)
+
+type decodeOptions struct {
+	useNumber     bool
+	preserveOrder bool
+}
+
+// UseNumber decodes json numbers as json.Number instead of float64, preserving the precision of large integers
+// and decimals.
+func UseNumber() DecodeOption {
+	return func(o *decodeOptions) {
+		o.useNumber = true
+	}
+}
+
+// PreserveOrder decodes json objects as ordered maps, keeping the keys in source order when getting, setting,
+// iterating and marshalling.
+func PreserveOrder() DecodeOption {
+	return func(o *decodeOptions) {
+		o.preserveOrder = true
+	}
+}
+
+func newDecodeOptions(opts []DecodeOption) decodeOptions {
+	var options decodeOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
 // UnmarshalMap parses the json and returns the map.
-func UnmarshalMap(v string) (*Map, error) {
-	result := Map{}
-	if err := json.Unmarshal([]byte(v), &result.m); err != nil {
+func UnmarshalMap(v string, opts ...DecodeOption) (*Map, error) {
+	options := newDecodeOptions(opts)
+	if options.preserveOrder {
+		value, err := unmarshalRaw([]byte(v), options)
+		if err != nil {
+			return nil, err
+		}
+		result, ok := value.(*Map)
+		if !ok {
+			return nil, fmt.Errorf("cannot unmarshal %T into an ordered map", value)
+		}
+		return result, nil
+	}
+
+	result := Map{refs: &references{}}
+	if err := unmarshal([]byte(v), &result.m, options); err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
 
 // Unmarshal parses the json and returns the Value.
-func Unmarshal(jsonString string) (Value, error) {
-	var value any
-	if err := json.Unmarshal([]byte(jsonString), &value); err != nil {
+func Unmarshal(jsonString string, opts ...DecodeOption) (Value, error) {
+	return UnmarshalBytes([]byte(jsonString), opts...)
+}
+
+// UnmarshalBytes parses the json and returns the Value.
+func UnmarshalBytes(data []byte, opts ...DecodeOption) (Value, error) {
+	value, err := unmarshalRaw(data, newDecodeOptions(opts))
+	if err != nil {
 		return nil, err
 	}
 
 	return toPathValue(value)
 }
 
+// Decode reads a single json document from the reader and returns the Value.
+// It returns an error when the reader contains data after the document.
+// To read multiple documents from a stream, use a Decoder.
+func Decode(r io.Reader, opts ...DecodeOption) (Value, error) {
+	options := newDecodeOptions(opts)
+	dec := newJSONDecoder(r, options)
+	value, err := decodeRaw(dec, options)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkEOF(dec); err != nil {
+		return nil, err
+	}
+
+	return toPathValue(value)
+}
+
+// unmarshal parses the json into v, which must not be an ordered map.
+func unmarshal(data []byte, v any, options decodeOptions) error {
+	if !options.useNumber {
+		return json.Unmarshal(data, v)
+	}
+	dec := newJSONDecoder(bytes.NewReader(data), options)
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	return checkEOF(dec)
+}
+
+// unmarshalRaw parses the json into a raw json value.
+func unmarshalRaw(data []byte, options decodeOptions) (any, error) {
+	if !options.preserveOrder {
+		var value any
+		err := unmarshal(data, &value, options)
+		return value, err
+	}
+
+	dec := newJSONDecoder(bytes.NewReader(data), options)
+	value, err := decodeOrdered(dec)
+	if err != nil {
+		return nil, err
+	}
+	return value, checkEOF(dec)
+}
+
+func newJSONDecoder(r io.Reader, options decodeOptions) *json.Decoder {
+	dec := json.NewDecoder(r)
+	if options.useNumber {
+		dec.UseNumber()
+	}
+	return dec
+}
+
+// decodeRaw decodes the next json document into a raw json value.
+func decodeRaw(dec *json.Decoder, options decodeOptions) (any, error) {
+	if options.preserveOrder {
+		return decodeOrdered(dec)
+	}
+	var value any
+	err := dec.Decode(&value)
+	return value, err
+}
+
+// checkEOF returns an error when there's more data after the json document.
+func checkEOF(dec *json.Decoder) error {
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return errors.New("invalid data after the json document")
+	}
+	return nil
+}
+
 // MarshalMap returns the json string for the provided map.
 func MarshalMap(m *Map) (string, error) {
-	blob, err := json.Marshal(m.m)
+	blob, err := json.Marshal(m)
 	if err != nil {
 		return "", err
 	}
@@ -35,8 +159,13 @@ func MarshalMap(m *Map) (string, error) {
 }
 
 // Marshal returns the json string for the provided Value.
+// Values created with UnmarshalLazy() that were not modified keep the order of the source.
 func Marshal(value Value) (string, error) {
-	blob, err := json.Marshal(value.Value())
+	var rawValue any = value
+	if _, ok := value.(json.Marshaler); !ok {
+		rawValue = toRawValue(value)
+	}
+	blob, err := json.Marshal(rawValue)
 	if err != nil {
 		return "", err
 	}
@@ -44,6 +173,15 @@ func Marshal(value Value) (string, error) {
 	return string(blob), nil
 }
 
+// rawJSON returns the compact json encoding of the value, nil when it can't be encoded.
+func rawJSON(value Value) []byte {
+	blob, err := json.Marshal(toRawValue(value))
+	if err != nil {
+		return nil
+	}
+	return blob
+}
+
 // MustUnmarshalMap is a non-fallible version of UnmarshalMap() used for static variables and tests.
 func MustUnmarshalMap(v string) *Map {
 	return must(UnmarshalMap(v))
@@ -54,31 +192,37 @@ func MustUnmarshalMap(v string) *Map {
 //
 // Note that the provided map is not copied, so any modification in the original map will reflect in the Map.
 func FromJSONMap(m map[string]any) *Map {
-	return &Map{m: m}
+	return newMap(m)
 }
 
 // JSONValue is the type constraint for a json value.
 type JSONValue interface {
-	float64 | string | bool | map[string]any | []any
+	float64 | json.Number | string | bool | map[string]any | []any
 }
 
 // From creates a new Value from a JSONValue.
 //
-// It panics if the value is not float64, string, bool, map or array.
-// In the case of maps and slices it expects the child values to be composed only by valid json values
-// (float64, string, bool, map and slice). Note that the provided value is not copied, so any modification in the
-// original map/slice will reflect in the Value.
+// The child values of maps and slices are converted as with FromGo(), it panics if they can't be represented in
+// json. Maps and slices composed only by json values are not copied, so any modification in the original map/slice
+// will reflect in the Value.
 func From[T JSONValue](value T) Value {
-	return mustToPathValue(value)
+	return must(FromGo(value))
 }
 
-// FromAny creates a new Value from an any value.
+// FromAny creates a new Value from any Go value, see FromGo().
 //
-// In the case of maps and slices it expects the child values to be composed only by valid json values
-// (float64, string, bool, map and slice). Note that the provided value is not copied, so any modification in the
-// original map/slice will reflect in the Value.
+// It panics if the value can't be represented in json. Maps and slices composed only by json values are not copied,
+// so any modification in the original map/slice will reflect in the Value.
 func FromAny(value any) Value {
-	return mustToPathValue(value)
+	return must(FromGo(value))
+}
+
+// CopyFrom creates a new Value from any Go value as FromAny(), copying the maps and slices so that the Value is
+// independent from the original value. It can be used to create multiple documents from a template.
+//
+// It panics if the value can't be represented in json.
+func CopyFrom(value any) Value {
+	return FromAny(value).Clone()
 }
 
 func must[T any](value T, err error) T {
@@ -124,18 +268,23 @@ func toPathValue(jsonValue any) (Value, error) {
 	switch v := jsonValue.(type) {
 	case float64:
 		return &scalar{v: v}, nil
+	case json.Number:
+		return &scalar{v: v}, nil
 	case string:
 		return &scalar{v: v}, nil
 	case bool:
 		return &scalar{v: v}, nil
 	case map[string]any:
-		return &Map{m: v}, nil
-	case []any:
-		newSlice := make(Slice, 0, len(v))
-		for _, item := range v {
-			newSlice = append(newSlice, mustToPathValue(item))
+		return newMap(v), nil
+	case *Map:
+		return v, nil
+	case json.RawMessage:
+		if !json.Valid(v) {
+			return nil, errors.New("invalid json in json.RawMessage")
 		}
-		return newSlice, nil
+		return &LazyValue{data: bytes.TrimSpace(v)}, nil
+	case []any:
+		return newArray(v), nil
 	default:
 		// A developer issue
 		return nil, fmt.Errorf("type %T not supported, only values from json decoding are supported", jsonValue)
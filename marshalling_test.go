@@ -1,7 +1,12 @@
 package jsonnav
 
 import (
+	"encoding/json"
+	"io"
+	"math"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -67,6 +72,104 @@ func TestUnmarshal(t *testing.T) {
 	})
 }
 
+func TestUnmarshalBytes(t *testing.T) {
+	t.Run("should return a valid value", func(t *testing.T) {
+		result, err := UnmarshalBytes([]byte(`{"a": [1, "b"]}`))
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"a": []any{1.0, "b"}}, result.Value())
+	})
+
+	t.Run("should fail when it's not valid json", func(t *testing.T) {
+		_, err := UnmarshalBytes([]byte(`{"a": `))
+		require.Error(t, err)
+	})
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("should return a valid value from the reader", func(t *testing.T) {
+		result, err := Decode(strings.NewReader(`{"a": {"b": true}}` + "\n"))
+		require.NoError(t, err)
+		require.True(t, result.Get("a.b").Bool())
+	})
+
+	t.Run("should fail when it's not valid json", func(t *testing.T) {
+		_, err := Decode(strings.NewReader(`{"a": `))
+		require.Error(t, err)
+		_, err = Decode(strings.NewReader(``))
+		require.ErrorIs(t, err, io.EOF)
+	})
+
+	t.Run("should fail when there is data after the document", func(t *testing.T) {
+		_, err := Decode(strings.NewReader(`{"a": 1} {"a": 2}`))
+		require.ErrorContains(t, err, "invalid data after the json document")
+	})
+}
+
+func TestUseNumber(t *testing.T) {
+	const doc = `{"id": 9007199254740993, "price": 0.1000000000000000055511151231257827, "items": [
+		{"id": 9007199254740993, "name": "a"},
+		{"id": 9007199254740992, "name": "b"}
+	]}`
+
+	t.Run("should preserve the precision of large integers and decimals", func(t *testing.T) {
+		value, err := Unmarshal(doc, UseNumber())
+		require.NoError(t, err)
+		require.Equal(t, json.Number("9007199254740993"), value.Get("id").Value())
+		require.Equal(t, int64(9007199254740993), value.Get("id").Int())
+		require.Equal(t, "9007199254740993", value.Get("id").String())
+		require.Equal(t, 0.1, value.Get("price").Float())
+		require.Equal(t, int64(0), value.Get("price").Int())
+		require.True(t, value.Get("id").IsFloat())
+
+		// Precision is lost without the option
+		value, err = Unmarshal(doc)
+		require.NoError(t, err)
+		require.Equal(t, int64(9007199254740992), value.Get("id").Int())
+	})
+
+	t.Run("should round-trip numbers", func(t *testing.T) {
+		value, err := UnmarshalMap(`{"id":9007199254740993,"price":0.1000000000000000055511151231257827}`, UseNumber())
+		require.NoError(t, err)
+		str, err := Marshal(value)
+		require.NoError(t, err)
+		require.Equal(t, `{"id":9007199254740993,"price":0.1000000000000000055511151231257827}`, str)
+
+		var sb strings.Builder
+		require.NoError(t, Encode(&sb, value, WithSortedKeys()))
+		require.Equal(t, str+"\n", sb.String())
+	})
+
+	t.Run("should compare numbers exactly in conditions", func(t *testing.T) {
+		value, err := Unmarshal(doc, UseNumber())
+		require.NoError(t, err)
+		require.Equal(t, "a", value.Get("items.#(id==9007199254740993).name").String())
+		require.Equal(t, "b", value.Get("items.#(id<9007199254740993).name").String())
+		require.Equal(t, []any{"a", "b"}, value.Get("items.#(id>=9.007199254740992e15)#.name").Value())
+		require.False(t, value.Get(`items.#(id=="9007199254740993")`).Exists())
+		require.True(t, value.Get("price==0.1000000000000000055511151231257827").Exists())
+		require.False(t, value.Get("price==0.1").Exists())
+		require.True(t, value.Get("price==~true").Exists())
+	})
+
+	t.Run("should apply to decoders", func(t *testing.T) {
+		value, err := Decode(strings.NewReader(`[18446744073709551616]`), UseNumber())
+		require.NoError(t, err)
+		require.Equal(t, "18446744073709551616", value.Get("0").String())
+
+		value, err = NewDecoder(strings.NewReader(`1 9007199254740993`), UseNumber()).Decode()
+		require.NoError(t, err)
+		require.Equal(t, json.Number("1"), value.Value())
+
+		for value, err := range DecodeLines(strings.NewReader("9007199254740993\n"), UseNumber()) {
+			require.NoError(t, err)
+			require.Equal(t, int64(9007199254740993), value.Int())
+		}
+
+		_, err = UnmarshalBytes([]byte(`1 2`), UseNumber())
+		require.Error(t, err)
+	})
+}
+
 func TestFrom(t *testing.T) {
 	t.Run("should return a valid map", func(t *testing.T) {
 		v := map[string]any{
@@ -109,3 +212,92 @@ func TestFrom(t *testing.T) {
 		}
 	})
 }
+
+type track struct {
+	Title    string        `json:"title"`
+	Length   time.Duration `json:"length"`
+	Explicit bool          `json:"explicit,omitempty"`
+	Rating   *float32      `json:"rating,omitempty"`
+	Released time.Time     `json:"released"`
+	Skipped  string        `json:"-"`
+}
+
+func TestFromGo(t *testing.T) {
+	t.Run("should normalize Go values", func(t *testing.T) {
+		rating := float32(4.1)
+		value, err := FromGo(map[string]any{
+			"ints":    []int{1, 2},
+			"labels":  map[string]string{"a": "b"},
+			"ids":     map[int64]uint8{10: 1},
+			"big":     int64(9007199254740993),
+			"pointer": &rating,
+			"nil":     (*int)(nil),
+			"bytes":   []byte("hi"),
+			"tracks": []track{{
+				Title: "Yesterday", Length: 2 * time.Second, Skipped: "x",
+				Released: time.Date(1965, 8, 6, 0, 0, 0, 0, time.UTC),
+			}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{
+			"ints":    []any{1.0, 2.0},
+			"labels":  map[string]any{"a": "b"},
+			"ids":     map[string]any{"10": 1.0},
+			"big":     json.Number("9007199254740993"),
+			"pointer": 4.1,
+			"nil":     nil,
+			"bytes":   "aGk=",
+			"tracks": []any{map[string]any{
+				"title": "Yesterday", "length": 2e9, "released": "1965-08-06T00:00:00Z",
+			}},
+		}, value.Value())
+		require.Equal(t, "Yesterday", value.Get("tracks.0.title").String())
+		require.Equal(t, int64(9007199254740993), value.Get("big").Int())
+
+		value, err = FromGo(struct{ N json.Number }{"12"})
+		require.NoError(t, err)
+		require.Equal(t, json.Number("12"), value.Get("N").Value())
+	})
+
+	t.Run("should not copy json values", func(t *testing.T) {
+		m := map[string]any{"a": []any{1.0}}
+		value, err := FromGo(m)
+		require.NoError(t, err)
+		m["b"] = true
+		require.True(t, value.Get("b").Bool())
+
+		m = map[string]any{"a": []any{1.0}, "b": []any{1}}
+		value, err = FromGo(m)
+		require.NoError(t, err)
+		require.Equal(t, 1.0, value.Get("b.0").Value())
+		require.Equal(t, 1, m["b"].([]any)[0])
+	})
+
+	t.Run("should unwrap values", func(t *testing.T) {
+		inner := MustUnmarshalMap(`{"c": 1}`)
+		value := FromAny(map[string]any{"a": inner, "b": Slice{From("x")}, "s": struct{ V Value }{inner}})
+		require.Equal(t, map[string]any{
+			"a": map[string]any{"c": 1.0},
+			"b": []any{"x"},
+			"s": map[string]any{"V": map[string]any{"c": 1.0}},
+		}, value.Value())
+		require.Same(t, inner, must(FromGo(inner)))
+		require.Equal(t, 1.0, FromAny(1).Value())
+	})
+
+	t.Run("should return an error for unsupported values", func(t *testing.T) {
+		for _, v := range []any{
+			make(chan int),
+			map[string]any{"f": func() {}},
+			[]float64{math.NaN()},
+			map[[2]int]bool{{1, 2}: true},
+			json.Number("abc"),
+			[]any{json.Number("")},
+			struct{ N json.Number }{"1x"},
+		} {
+			_, err := FromGo(v)
+			require.ErrorIs(t, err, ErrTypeMismatch, "%T", v)
+		}
+		require.Panics(t, func() { FromAny(complex(1, 2)) })
+	})
+}
@@ -0,0 +1,223 @@
+package jsonnav
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ModifierFunc transforms a value in a path, for example `children.@reverse`.
+//
+// The arg contains the json argument provided after the modifier name, for example `@truncate:{"len":10}`.
+// When the argument is not valid json, arg is a string scalar containing the raw text. When no argument is
+// provided, arg is an undefined value.
+type ModifierFunc func(value Value, arg Value) Value
+
+var (
+	modifiersMu sync.RWMutex
+
+	// modifiers contains the registered path modifiers by name.
+	modifiers = map[string]ModifierFunc{
+		"reverse": reverseModifier,
+		"keys":    keysModifier,
+		"values":  valuesModifier,
+		"flatten": flattenModifier,
+		"join":    joinModifier,
+		"valid":   thisModifier,
+		"this":    thisModifier,
+		"tostr":   toStrModifier,
+		"fromstr": fromStrModifier,
+		"group":   groupModifier,
+	}
+)
+
+// AddModifier registers a custom path modifier that can be used in any path as `@name` or `@name:arg`.
+// Registering a modifier with the name of an existing one replaces it.
+//
+// It panics if the name is empty or contains special path characters.
+func AddModifier(name string, fn ModifierFunc) {
+	if name == "" || strings.ContainsAny(name, specialChars+":") {
+		panic("invalid modifier name: " + name)
+	}
+
+	modifiersMu.Lock()
+	defer modifiersMu.Unlock()
+	modifiers[name] = fn
+}
+
+// ModifierExists returns true when a modifier with the provided name is registered.
+func ModifierExists(name string) bool {
+	_, ok := getModifierFunc(name)
+	return ok
+}
+
+func getModifierFunc(name string) (ModifierFunc, bool) {
+	modifiersMu.RLock()
+	defer modifiersMu.RUnlock()
+	fn, ok := modifiers[name]
+	return fn, ok
+}
+
+// cutModifier parses a modifier path component like `@flatten` or `@flatten:{"deep":true}`.
+// It returns false when the component is not a known modifier.
+func cutModifier(component string) (fn ModifierFunc, arg Value, ok bool) {
+	if !strings.HasPrefix(component, "@") {
+		return nil, nil, false
+	}
+
+	name, rawArg, hasArg := strings.Cut(component[1:], ":")
+	fn, ok = getModifierFunc(name)
+	if !ok {
+		return nil, nil, false
+	}
+
+	arg = undefinedScalar
+	if hasArg {
+		var err error
+		if arg, err = Unmarshal(rawArg); err != nil {
+			// Use the raw text as argument
+			arg = &scalar{v: rawArg}
+		}
+	}
+	return fn, arg, true
+}
+
+// reverseModifier reverses the items of an array.
+func reverseModifier(value Value, _ Value) Value {
+	if !value.IsArray() {
+		return value
+	}
+
+	items := value.Array()
+	result := make(Slice, len(items))
+	for i, item := range items {
+		result[len(items)-1-i] = item
+	}
+	return result
+}
+
+// keysModifier returns the keys of an object in sorted order, or in source order for ordered objects.
+func keysModifier(value Value, _ Value) Value {
+	keys := objectKeys(value)
+	result := make(Slice, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, &scalar{v: key})
+	}
+	return result
+}
+
+// valuesModifier returns the values of an object in the same order as the keys modifier.
+func valuesModifier(value Value, _ Value) Value {
+	items := value.Map()
+	result := make(Slice, 0, len(items))
+	for _, key := range objectKeys(value) {
+		result = append(result, items[key])
+	}
+	return result
+}
+
+// objectKeys returns the keys of an object in the order of Map.Keys().
+func objectKeys(value Value) []string {
+	if m, ok := value.(*Map); ok {
+		return m.Keys()
+	}
+	return slices.Sorted(maps.Keys(value.Map()))
+}
+
+// flattenModifier flattens the child arrays of an array.
+// The arg `{"deep":true}` flattens the array recursively.
+func flattenModifier(value Value, arg Value) Value {
+	if !value.IsArray() {
+		return value
+	}
+	return flatten(value.Array(), arg.Get("deep").Bool())
+}
+
+func flatten(items Slice, deep bool) Slice {
+	result := make(Slice, 0, len(items))
+	for _, item := range items {
+		if !item.IsArray() {
+			result = append(result, item)
+			continue
+		}
+		if deep {
+			result = append(result, flatten(item.Array(), deep)...)
+		} else {
+			result = append(result, item.Array()...)
+		}
+	}
+	return result
+}
+
+// joinModifier merges the objects of an array into a single object.
+// When a key is present in more than one object, the last value is used.
+func joinModifier(value Value, _ Value) Value {
+	if !value.IsArray() {
+		return value
+	}
+
+	result := make(map[string]any)
+	for _, item := range value.Array() {
+		for key, child := range item.Map() {
+			result[key] = child.Value()
+		}
+	}
+	return newMap(result)
+}
+
+// thisModifier returns the value itself.
+// As values are always valid json, it's also used for the `@valid` modifier.
+func thisModifier(value Value, _ Value) Value {
+	return value
+}
+
+// toStrModifier converts the value to a string containing its json representation.
+func toStrModifier(value Value, _ Value) Value {
+	str, err := Marshal(value)
+	if err != nil {
+		return undefinedScalar
+	}
+	return &scalar{v: str}
+}
+
+// fromStrModifier parses the json contained in a string value.
+func fromStrModifier(value Value, _ Value) Value {
+	if !value.IsString() {
+		return undefinedScalar
+	}
+
+	result, err := Unmarshal(value.String())
+	if err != nil {
+		return undefinedScalar
+	}
+	return result
+}
+
+// groupModifier groups the arrays of an object into an array of objects, for example
+// `{"id":["1","2"],"val":[10,20]}` is converted to `[{"id":"1","val":10},{"id":"2","val":20}]`.
+func groupModifier(value Value, _ Value) Value {
+	if !value.IsObject() {
+		return undefinedScalar
+	}
+
+	var groups []map[string]any
+	items := value.Map()
+	for _, key := range slices.Sorted(maps.Keys(items)) {
+		if !items[key].IsArray() {
+			continue
+		}
+		for i, child := range items[key].Array() {
+			if i == len(groups) {
+				groups = append(groups, make(map[string]any))
+			}
+			groups[i][key] = child.Value()
+		}
+	}
+
+	result := make(Slice, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, newMap(group))
+	}
+	return result
+}
@@ -0,0 +1,125 @@
+package jsonnav
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestModifiers(t *testing.T) {
+	value := MustUnmarshalMap(friendsJSON)
+
+	t.Run("should reverse arrays", func(t *testing.T) {
+		require.Equal(t, []any{"Jack", "Alex", "Sara"}, value.Get("children.@reverse").Value())
+		require.Equal(t, "Jack", value.Get("children.@reverse.0").String())
+		require.Equal(t, []any{"Jane", "Roger", "Dale"}, value.Get("friends.@reverse.#.first").Value())
+		require.Equal(t, []any{"Jack", "Alex", "Sara"}, value.Get("children").Get("@reverse").Value())
+		require.Equal(t, "Tom", value.Get("name.@reverse.first").String())
+	})
+
+	t.Run("should return object keys and values", func(t *testing.T) {
+		require.Equal(t, []any{"first", "last"}, value.Get("name.@keys").Value())
+		require.Equal(t, []any{"Tom", "Anderson"}, value.Get("name.@values").Value())
+		require.Equal(t, []any{}, value.Get("children.@keys").Value())
+		require.Equal(t, "last", value.Get("name.@keys.1").String())
+	})
+
+	t.Run("should flatten arrays", func(t *testing.T) {
+		v := MustUnmarshalMap(`{"a": [1, [2], [3, [4, [5]]]]}`)
+		require.Equal(t, []any{1.0, 2.0, 3.0, []any{4.0, []any{5.0}}}, v.Get("a.@flatten").Value())
+		require.Equal(t, []any{1.0, 2.0, 3.0, 4.0, 5.0}, v.Get(`a.@flatten:{"deep":true}`).Value())
+		require.Equal(t, 5.0, v.Get(`a.@flatten:{"deep":true}.4`).Float())
+	})
+
+	t.Run("should join objects", func(t *testing.T) {
+		v := MustUnmarshalMap(`{"a": [{"first": "Tom", "age": 37}, {"age": 41}]}`)
+		require.Equal(t, map[string]any{"first": "Tom", "age": 41.0}, v.Get("a.@join").Value())
+		require.Equal(t, 41.0, v.Get("a.@join.age").Float())
+	})
+
+	t.Run("should convert to and from strings", func(t *testing.T) {
+		require.Equal(t, `{"first":"Tom","last":"Anderson"}`, value.Get("name.@tostr").String())
+		require.Equal(t, `"Tom"`, value.Get("name.first.@tostr").String())
+		v := MustUnmarshalMap(`{"a": "{\"b\": [1, 2]}", "invalid": "{"}`)
+		require.Equal(t, map[string]any{"b": []any{1.0, 2.0}}, v.Get("a.@fromstr").Value())
+		require.Equal(t, 2.0, v.Get("a.@fromstr.b.1").Float())
+		require.False(t, v.Get("invalid.@fromstr").Exists())
+	})
+
+	t.Run("should group arrays", func(t *testing.T) {
+		v := MustUnmarshalMap(`{"id": ["123", "456", "789"], "val": [2, 1]}`)
+		require.Equal(t, []any{
+			map[string]any{"id": "123", "val": 2.0},
+			map[string]any{"id": "456", "val": 1.0},
+			map[string]any{"id": "789"},
+		}, v.Get("@group").Value())
+		require.False(t, value.Get("children.@group").Exists())
+	})
+
+	t.Run("should return the same value with this and valid", func(t *testing.T) {
+		require.Equal(t, value, value.Get("@this"))
+		require.Equal(t, value, value.Get("@valid"))
+		require.Equal(t, "Tom", value.Get("@this.name.first").String())
+		require.Equal(t, "Sara", value.Get("children.@valid.0").String())
+	})
+
+	t.Run("should chain modifiers", func(t *testing.T) {
+		require.Equal(t, `["Jack","Alex","Sara"]`, value.Get("children.@reverse.@tostr").String())
+		require.Equal(t, []any{"last", "first"}, value.Get("name.@keys.@reverse").Value())
+	})
+
+	t.Run("should treat unknown modifiers as keys", func(t *testing.T) {
+		v := MustUnmarshalMap(`{"@unknown": 1, "a": {"@reverse": 2}}`)
+		require.Equal(t, 1.0, v.Get("@unknown").Float())
+		require.Equal(t, 2.0, v.Get(`a.\@reverse`).Float())
+		require.False(t, v.Get("missing.@reverse").Exists())
+	})
+}
+
+func TestAddModifier(t *testing.T) {
+	AddModifier("lowercase", func(value Value, _ Value) Value {
+		return From(strings.ToLower(value.String()))
+	})
+	AddModifier("truncate", func(value Value, arg Value) Value {
+		length := int(arg.Get("len").Int())
+		if str := value.String(); len(str) > length {
+			return From(str[:length])
+		}
+		return value
+	})
+	AddModifier("sum", func(value Value, _ Value) Value {
+		total := 0.0
+		for _, item := range value.Array() {
+			total += item.Float()
+		}
+		return From(total)
+	})
+	AddModifier("prefix", func(value Value, arg Value) Value {
+		return From(arg.String() + value.String())
+	})
+
+	value := MustUnmarshalMap(friendsJSON)
+
+	t.Run("should apply custom modifiers", func(t *testing.T) {
+		require.True(t, ModifierExists("lowercase"))
+		require.True(t, ModifierExists("reverse"))
+		require.False(t, ModifierExists("uppercase"))
+		require.Equal(t, "anderson", value.Get("name.last.@lowercase").String())
+		require.Equal(t, 159.0, value.Get("friends.#.age").Get("@sum").Float())
+		require.Equal(t, []any{"dale", "roger", "jane"}, value.Get("friends.#.first.@lowercase").Value())
+	})
+
+	t.Run("should provide the modifier argument", func(t *testing.T) {
+		require.Equal(t, "Ande", value.Get(`name.last.@truncate:{"len":4}`).String())
+		require.Equal(t, "Mr. Tom", value.Get(`name.first.@prefix:"Mr. "`).String())
+		require.Equal(t, "+Tom", value.Get(`name.first.@prefix:+`).String())
+		require.Equal(t, "Tom", value.Get(`name.first.@prefix`).String())
+	})
+
+	t.Run("should panic with invalid names", func(t *testing.T) {
+		require.Panics(t, func() { AddModifier("", nil) })
+		require.Panics(t, func() { AddModifier("a.b", nil) })
+		require.Panics(t, func() { AddModifier("a:b", nil) })
+	})
+}
@@ -0,0 +1,86 @@
+package jsonnav
+
+import "strings"
+
+// multipath represents a new object `{name.first,"years":age}` or array `[name.first,age]` built from the values
+// of multiple paths.
+type multipath struct {
+	object bool
+	keys   []string
+	paths  [][]component
+}
+
+// parseMultipath parses a multipath component, where offset is the position of the component within the full
+// expression. It returns false when the component is not a multipath.
+func (p *parser) parseMultipath(component string, offset int) (*multipath, bool, error) {
+	if len(component) < 2 {
+		return nil, false, nil
+	}
+	first, last := component[0], component[len(component)-1]
+	isObject := first == '{' && last == '}'
+	if !isObject && (first != '[' || last != ']') {
+		return nil, false, nil
+	}
+
+	mp := &multipath{object: isObject}
+	list := component[1 : len(component)-1]
+	if list == "" {
+		// Empty object or array
+		return mp, true, nil
+	}
+	itemOffset := offset + 1
+	for _, item := range splitList(list) {
+		key, path := "", item
+		if isObject {
+			key, path = cutMultipathKey(item)
+		}
+		components, err := p.parseComponents(path, itemOffset+len(item)-len(path))
+		if err != nil {
+			return nil, true, err
+		}
+		mp.keys = append(mp.keys, key)
+		mp.paths = append(mp.paths, components)
+		itemOffset += len(item) + 1
+	}
+	return mp, true, nil
+}
+
+// get builds the object or array from the paths evaluated on the value. Paths that don't exist are omitted.
+func (mp *multipath) get(value Value) Value {
+	if mp.object {
+		// Keep the order of the paths as gjson does
+		result := newOrderedMap()
+		for i, path := range mp.paths {
+			if child := getComponents(value, path); child.Exists() {
+				result.setRaw(mp.keys[i], toRawValue(child))
+			}
+		}
+		return result
+	}
+
+	result := make(Slice, 0, len(mp.paths))
+	for _, path := range mp.paths {
+		if child := getComponents(value, path); child.Exists() {
+			result = append(result, child)
+		}
+	}
+	return result
+}
+
+// cutMultipathKey returns the key and the path of an object multipath item.
+// The key is either provided explicitly (`"key":path`) or the last component of the path.
+func cutMultipathKey(item string) (key string, path string) {
+	if strings.HasPrefix(item, `"`) {
+		if index := indexUnnested(item, ":"); index != -1 {
+			key, _ = unquoteLiteral(item[:index])
+			return key, item[index+1:]
+		}
+	}
+
+	// Use the last component of the path as key
+	component, remainingPath := splitPath(item)
+	for remainingPath != "" {
+		component, remainingPath = splitPath(remainingPath)
+	}
+	return unescapeKey(component), item
+}
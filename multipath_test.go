@@ -0,0 +1,54 @@
+package jsonnav
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultipath(t *testing.T) {
+	value := MustUnmarshalMap(friendsJSON)
+
+	t.Run("should build objects", func(t *testing.T) {
+		require.Equal(t, map[string]any{
+			"first":       "Tom",
+			"age":         37.0,
+			"the_murphys": []any{"Dale", "Jane"},
+		}, value.Get(`{name.first,age,"the_murphys":friends.#(last="Murphy")#.first}`).Value())
+		require.Equal(t, map[string]any{"first": "Tom"}, value.Get(`{name.first,missing}`).Value())
+		require.Equal(t, map[string]any{"a.b": "Tom"}, value.Get(`{"a.b":name.first}`).Value())
+		require.Equal(t, map[string]any{}, value.Get(`{}`).Value())
+	})
+
+	t.Run("should build arrays", func(t *testing.T) {
+		require.Equal(t, []any{"Tom", 37.0}, value.Get(`[name.first,age,missing]`).Value())
+		require.Equal(t, []any{[]any{"Sara", "Alex", "Jack"}, "Sara"}, value.Get(`[children,children.0]`).Value())
+	})
+
+	t.Run("should support nested multipaths and modifiers", func(t *testing.T) {
+		require.Equal(t, map[string]any{
+			"name": map[string]any{"first": "Tom", "last": "Anderson"},
+			"kids": []any{"Jack", "Alex", "Sara"},
+			"last": []any{"Murphy", "Craig", "Murphy"},
+		}, value.Get(`{"name":{name.first,name.last},"kids":children.@reverse,friends.#.last}`).Value())
+		require.Equal(t, []any{
+			map[string]any{"first": "Dale", "age": 44.0},
+			map[string]any{"first": "Roger", "age": 68.0},
+			map[string]any{"first": "Jane", "age": 47.0},
+		}, value.Get(`friends.#.{first,age}`).Value())
+	})
+
+	t.Run("should search the remaining path in the result", func(t *testing.T) {
+		require.Equal(t, "Tom", value.Get(`{name.first,age}.first`).String())
+		require.Equal(t, 37.0, value.Get(`[name.first,age].1`).Float())
+		require.Equal(t, "Tom", value.Get("name").Get(`{first}.first`).String())
+		require.False(t, value.Get(`missing.{first}`).Exists())
+	})
+
+	t.Run("should keep the order of the paths", func(t *testing.T) {
+		str, err := Marshal(value.Get(`{name.last,age,"kids":children.#,name.first,"nested":{age,name.last}}`))
+		require.NoError(t, err)
+		require.Equal(t, `{"last":"Anderson","age":37,"kids":3,"first":"Tom","nested":{"age":37,"last":"Anderson"}}`,
+			str)
+	})
+}
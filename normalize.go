@@ -0,0 +1,318 @@
+package jsonnav
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+)
+
+// FromGo creates a new Value from any Go value, converting it into json values following the encoding/json rules
+// for struct tags, maps, slices and marshalers.
+//
+// It returns an error for values that can't be represented in json, like channels, functions, NaN or maps with
+// unsupported key types. Maps and slices composed only by json values are not copied, so any modification in the
+// original map/slice will reflect in the Value.
+func FromGo(value any) (Value, error) {
+	if v, ok := value.(Value); ok {
+		return v, nil
+	}
+	rawValue, _, err := normalize(value)
+	if err != nil {
+		return nil, err
+	}
+	return toPathValue(rawValue)
+}
+
+var numberType = reflect.TypeFor[json.Number]()
+
+// maxExactInt is the maximum integer that float64 can represent exactly.
+const maxExactInt = 1 << 53
+
+// normalize converts the Go value into a raw json value, returning whether the value was converted.
+// Maps and slices are only copied when some of their items are converted.
+func normalize(value any) (any, bool, error) {
+	switch v := value.(type) {
+	case nil, string, bool:
+		return v, false, nil
+	case json.Number:
+		if !isNumberText(v.String()) {
+			return nil, false, fmt.Errorf("%w: invalid number %q", ErrTypeMismatch, v)
+		}
+		return v, false, nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, false, fmt.Errorf("%w: unsupported number %v", ErrTypeMismatch, v)
+		}
+		return v, false, nil
+	case json.RawMessage:
+		rawValue, err := unmarshalRaw(v, decodeOptions{})
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid json in json.RawMessage: %w", err)
+		}
+		return rawValue, true, nil
+	case map[string]any:
+		return normalizeMap(v)
+	case []any:
+		return normalizeSlice(v)
+	case Value:
+		// Ordered maps are converted to Go maps to be contained in any object
+		return plainValue(toRawValue(v)), true, nil
+	default:
+		rawValue, err := normalizeReflect(reflect.ValueOf(value))
+		return rawValue, true, err
+	}
+}
+
+func normalizeMap(m map[string]any) (any, bool, error) {
+	var result map[string]any
+	for key, item := range m {
+		rawItem, changed, err := normalize(item)
+		if err != nil {
+			return nil, false, err
+		}
+		if changed && result == nil {
+			result = maps.Clone(m)
+		}
+		if result != nil {
+			result[key] = rawItem
+		}
+	}
+	if result == nil {
+		return m, false, nil
+	}
+	return result, true, nil
+}
+
+func normalizeSlice(s []any) (any, bool, error) {
+	var result []any
+	for i, item := range s {
+		rawItem, changed, err := normalize(item)
+		if err != nil {
+			return nil, false, err
+		}
+		if changed && result == nil {
+			result = slices.Clone(s)
+		}
+		if result != nil {
+			result[i] = rawItem
+		}
+	}
+	if result == nil {
+		return s, false, nil
+	}
+	return result, true, nil
+}
+
+// normalizeReflect converts the Go value into a raw json value using reflection.
+func normalizeReflect(rv reflect.Value) (any, error) {
+	if !rv.IsValid() {
+		return nil, nil
+	}
+	if (rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface) && rv.IsNil() {
+		return nil, nil
+	}
+	if rv.CanInterface() {
+		if v, ok := rv.Interface().(Value); ok {
+			return plainValue(toRawValue(v)), nil
+		}
+		if rawValue, ok, err := normalizeMarshaler(rv); ok {
+			return rawValue, err
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer:
+		return normalizeReflect(rv.Elem())
+	case reflect.Interface:
+		rawValue, _, err := normalize(rv.Elem().Interface())
+		return rawValue, err
+	case reflect.Bool:
+		return rv.Bool(), nil
+	case reflect.String:
+		if rv.Type() == numberType {
+			// json.Number fields are encoded as numbers
+			rawValue, _, err := normalize(json.Number(rv.String()))
+			return rawValue, err
+		}
+		return rv.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n := rv.Int()
+		if n < -maxExactInt || n > maxExactInt {
+			// Keep the precision of large integers
+			return json.Number(strconv.FormatInt(n, 10)), nil
+		}
+		return float64(n), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n := rv.Uint()
+		if n > maxExactInt {
+			return json.Number(strconv.FormatUint(n, 10)), nil
+		}
+		return float64(n), nil
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, fmt.Errorf("%w: unsupported number %v", ErrTypeMismatch, f)
+		}
+		if rv.Kind() == reflect.Float32 {
+			// Use the shortest representation of the float32 as encoding/json does
+			f, _ = strconv.ParseFloat(strconv.FormatFloat(f, 'g', -1, 32), 64)
+		}
+		return f, nil
+	case reflect.Slice:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			// Byte slices are encoded as base64 strings
+			return base64.StdEncoding.EncodeToString(rv.Bytes()), nil
+		}
+		return normalizeItems(rv)
+	case reflect.Array:
+		return normalizeItems(rv)
+	case reflect.Map:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		return normalizeReflectMap(rv)
+	case reflect.Struct:
+		return normalizeStruct(rv)
+	default:
+		return nil, fmt.Errorf("%w: unsupported type %s", ErrTypeMismatch, rv.Type())
+	}
+}
+
+// normalizeMarshaler uses the json.Marshaler or encoding.TextMarshaler implementation of the Go value, returning
+// false when the type doesn't implement them.
+func normalizeMarshaler(rv reflect.Value) (any, bool, error) {
+	target := rv
+	if rv.Kind() != reflect.Pointer && rv.CanAddr() {
+		// Methods with pointer receivers
+		target = rv.Addr()
+	}
+
+	switch marshaler := target.Interface().(type) {
+	case json.Marshaler:
+		data, err := marshaler.MarshalJSON()
+		if err != nil {
+			return nil, true, err
+		}
+		rawValue, err := unmarshalRaw(data, decodeOptions{})
+		return rawValue, true, err
+	case encoding.TextMarshaler:
+		text, err := marshaler.MarshalText()
+		return string(text), true, err
+	default:
+		return nil, false, nil
+	}
+}
+
+func normalizeItems(rv reflect.Value) (any, error) {
+	result := make([]any, rv.Len())
+	for i := range result {
+		rawItem, err := normalizeReflect(rv.Index(i))
+		if err != nil {
+			return nil, err
+		}
+		result[i] = rawItem
+	}
+	return result, nil
+}
+
+func normalizeReflectMap(rv reflect.Value) (any, error) {
+	result := make(map[string]any, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		key, err := normalizeKey(iter.Key())
+		if err != nil {
+			return nil, err
+		}
+		rawItem, err := normalizeReflect(iter.Value())
+		if err != nil {
+			return nil, err
+		}
+		result[key] = rawItem
+	}
+	return result, nil
+}
+
+// normalizeKey returns the object key of a Go map key.
+func normalizeKey(rv reflect.Value) (string, error) {
+	if rv.Kind() == reflect.String {
+		return rv.String(), nil
+	}
+	if rv.CanInterface() {
+		if marshaler, ok := rv.Interface().(encoding.TextMarshaler); ok {
+			text, err := marshaler.MarshalText()
+			return string(text), err
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	default:
+		return "", fmt.Errorf("%w: unsupported map key type %s", ErrTypeMismatch, rv.Type())
+	}
+}
+
+func normalizeStruct(rv reflect.Value) (any, error) {
+	result := make(map[string]any)
+	for _, field := range structFields(rv.Type()) {
+		fv, ok := readField(rv, field.index)
+		if !ok || (field.omitEmpty && isEmptyValue(fv)) {
+			continue
+		}
+		rawItem, err := normalizeReflect(fv)
+		if err != nil {
+			return nil, err
+		}
+		if field.quoted && rawItem != nil {
+			// Scalars are encoded within json strings
+			blob, err := json.Marshal(rawItem)
+			if err != nil {
+				return nil, err
+			}
+			rawItem = string(blob)
+		}
+		result[field.name] = rawItem
+	}
+	return result, nil
+}
+
+// readField returns the nested struct field, returning false when an embedded struct pointer is nil.
+func readField(rv reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				return reflect.Value{}, false
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+	return rv, true
+}
+
+// isEmptyValue determines whether the field should be omitted with the omitempty option.
+func isEmptyValue(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return rv.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return rv.IsZero()
+	default:
+		return false
+	}
+}
@@ -0,0 +1,212 @@
+package jsonnav
+
+import (
+	"strconv"
+	"strings"
+)
+
+// specialChars contains the characters that have a special meaning within a path component.
+const specialChars = `\.|#@*?!=<>%()[]{},"`
+
+// Escape escapes the special characters in the key, so it can be used as a path component.
+// For example, the key "fav.movie" can be accessed using the path `fav\.movie`.
+func Escape(key string) string {
+	if !strings.ContainsAny(key, specialChars) {
+		return key
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(key); i++ {
+		if strings.IndexByte(specialChars, key[i]) != -1 {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(key[i])
+	}
+	return b.String()
+}
+
+// unescapeKey removes the escape characters from a path component.
+func unescapeKey(key string) string {
+	if !strings.Contains(key, `\`) {
+		return key
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(key); i++ {
+		if key[i] == '\\' && i+1 < len(key) {
+			i++
+		}
+		b.WriteByte(key[i])
+	}
+	return b.String()
+}
+
+// hasWildcard determines whether the path component contains unescaped `*` or `?` wildcards.
+func hasWildcard(key string) bool {
+	for i := 0; i < len(key); i++ {
+		switch key[i] {
+		case '\\':
+			i++
+		case '*', '?':
+			return true
+		}
+	}
+	return false
+}
+
+// splitPath returns the first component of the path and the path remaining after the separator (`.` or `|`).
+// Escaped separators and separators within parentheses, brackets, braces or string literals are ignored.
+func splitPath(path string) (component string, remainingPath string) {
+	index := indexUnnested(path, ".|")
+	if index == -1 {
+		return path, ""
+	}
+	return path[:index], path[index+1:]
+}
+
+// isPiped determines whether the first component of the path is followed by a pipe separator.
+func isPiped(path string, component string) bool {
+	return len(path) > len(component) && path[len(component)] == '|'
+}
+
+// cutPipe splits the path at the first pipe separator.
+// The path before the pipe is applied to each element when mapping over an array, while the path after the pipe
+// is applied to the result as a whole, for example `friends.#.age|0`.
+func cutPipe(path string) (before string, after string) {
+	index := indexUnnested(path, "|")
+	if index == -1 {
+		return path, ""
+	}
+	return path[:index], path[index+1:]
+}
+
+// splitList splits a comma separated list, like the items of a multipath.
+// Escaped commas and commas within parentheses, brackets, braces or string literals are not considered separators.
+func splitList(list string) []string {
+	var items []string
+	for {
+		index := indexUnnested(list, ",")
+		if index == -1 {
+			return append(items, list)
+		}
+		items = append(items, list[:index])
+		list = list[index+1:]
+	}
+}
+
+// indexUnnested returns the index of the first instance of any of the separators that is not escaped or nested
+// within parentheses, brackets, braces or string literals.
+func indexUnnested(path string, separators string) int {
+	depth := 0
+	inString := false
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		switch {
+		case inString:
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '\\':
+			i++
+		case c == '"':
+			inString = true
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case c == ')' || c == ']' || c == '}':
+			if depth > 0 {
+				depth--
+			}
+		case depth == 0 && strings.IndexByte(separators, c) != -1:
+			return i
+		}
+	}
+	return -1
+}
+
+// endsWithSeparator determines whether the path ends with an unescaped separator.
+func endsWithSeparator(path string) bool {
+	last := len(path) - 1
+	if last < 0 || (path[last] != '.' && path[last] != '|') {
+		return false
+	}
+
+	// The separator is escaped when preceded by an odd number of backslashes
+	backslashes := 0
+	for i := last - 1; i >= 0 && path[i] == '\\'; i-- {
+		backslashes++
+	}
+	return backslashes%2 == 0
+}
+
+// indexClosingQuote returns the index of the quote closing the string literal starting at the provided index.
+// It returns -1 when the string literal is not closed.
+func indexClosingQuote(path string, start int) int {
+	for i := start + 1; i < len(path); i++ {
+		if path[i] == '\\' {
+			i++
+		} else if path[i] == '"' {
+			return i
+		}
+	}
+	return -1
+}
+
+// cutQuery parses a query component like `#(age>45)` or `#(age>45)#`, returning the inner expression and
+// whether all the matches should be returned.
+func cutQuery(component string) (expr string, all bool, ok bool) {
+	if !strings.HasPrefix(component, "#(") {
+		return "", false, false
+	}
+	expr = component[2:]
+	if strings.HasSuffix(expr, ")#") {
+		return expr[:len(expr)-2], true, true
+	}
+	if strings.HasSuffix(expr, ")") {
+		return expr[:len(expr)-1], false, true
+	}
+	return "", false, false
+}
+
+// cutLiteral returns the condition literal at the beginning of the path and the path remaining after it.
+// Unquoted literals end at the first separator, unless it's the decimal separator of a number.
+func cutLiteral(path string) (literal string, remainingPath string) {
+	end := len(path)
+	if strings.HasPrefix(path, `"`) {
+		if index := indexClosingQuote(path, 0); index != -1 {
+			end = index + 1
+		}
+	} else {
+		for i := 0; i < len(path); i++ {
+			if path[i] != '.' && path[i] != '|' {
+				continue
+			}
+			if path[i] == '.' && isDecimalSeparator(path, i) {
+				continue
+			}
+			end = i
+			break
+		}
+	}
+
+	literal, remainingPath = path[:end], path[end:]
+	if remainingPath != "" {
+		// Remove the separator
+		remainingPath = remainingPath[1:]
+	}
+	return literal, remainingPath
+}
+
+// isDecimalSeparator determines whether the dot at the provided index separates the integer and fractional parts
+// of a number.
+func isDecimalSeparator(path string, index int) bool {
+	integerPart := path[:index]
+	if strings.Contains(integerPart, ".") {
+		return false
+	}
+	if _, err := strconv.ParseInt(integerPart, 10, 64); err != nil {
+		return false
+	}
+	return index+1 < len(path) && path[index+1] >= '0' && path[index+1] <= '9'
+}
@@ -0,0 +1,51 @@
+package jsonnav
+
+import "unicode/utf8"
+
+// matchPattern determines whether the string matches the wildcard pattern, where `*` matches any sequence of
+// characters and `?` matches a single character. Wildcards can be escaped using a backslash.
+func matchPattern(str, pattern string) bool {
+	s, p := 0, 0
+	// Position of the last star in the pattern and the string position it's currently matching up to
+	starP, starS := -1, 0
+	for s < len(str) {
+		if p < len(pattern) {
+			switch pattern[p] {
+			case '*':
+				starP, starS = p, s
+				p++
+				continue
+			case '?':
+				_, size := utf8.DecodeRuneInString(str[s:])
+				s += size
+				p++
+				continue
+			case '\\':
+				if p+1 < len(pattern) && pattern[p+1] == str[s] {
+					p += 2
+					s++
+					continue
+				}
+			default:
+				if pattern[p] == str[s] {
+					p++
+					s++
+					continue
+				}
+			}
+		}
+		if starP == -1 {
+			return false
+		}
+
+		// Backtrack: let the last star consume one more character
+		_, size := utf8.DecodeRuneInString(str[starS:])
+		starS += size
+		s, p = starS, starP+1
+	}
+
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
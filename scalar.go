@@ -1,10 +1,15 @@
 package jsonnav
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
 	"strconv"
 )
 
-// scalar represents either a boolean, float64 or string type. Inner value can be nil.
+// scalar represents either a boolean, float64, json.Number or string type. Inner value can be nil.
 type scalar struct {
 	v any
 }
@@ -38,10 +43,12 @@ func (s *scalar) IsFloat() bool {
 	if s.v == nil {
 		return false
 	}
-	if _, ok := s.v.(float64); ok {
+	switch s.v.(type) {
+	case float64, json.Number:
 		return true
+	default:
+		return false
 	}
-	return false
 }
 
 func (s *scalar) IsBool() bool {
@@ -73,6 +80,9 @@ func (s *scalar) Float() float64 {
 	switch v := s.v.(type) {
 	case float64:
 		return v
+	case json.Number:
+		n, _ := v.Float64()
+		return n
 	case string:
 		n, _ := strconv.ParseFloat(v, 64)
 		return n
@@ -88,6 +98,13 @@ func (s *scalar) Int() int64 {
 	switch v := s.v.(type) {
 	case float64:
 		return int64(v)
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		// Decimal or exponent notation
+		n, _ := v.Float64()
+		return int64(n)
 	case string:
 		n, _ := strconv.ParseInt(v, 10, 64)
 		return n
@@ -96,6 +113,138 @@ func (s *scalar) Int() int64 {
 	}
 }
 
+func (s *scalar) Uint() uint64 {
+	switch v := s.v.(type) {
+	case float64:
+		return floatToUint(v)
+	case json.Number:
+		if n, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+			return n
+		}
+		// Decimal or exponent notation
+		n, _ := v.Float64()
+		return floatToUint(n)
+	case string:
+		n, _ := strconv.ParseUint(v, 10, 64)
+		return n
+	default:
+		return 0
+	}
+}
+
+// floatToUint converts the float to uint64, returning 0 when it's out of the range of uint64.
+func floatToUint(f float64) uint64 {
+	// math.MaxUint64 is 2^64 as a float64
+	if !(f >= 0 && f < math.MaxUint64) {
+		return 0
+	}
+	return uint64(f)
+}
+
+func (s *scalar) IsInt() bool {
+	if !s.IsFloat() {
+		return false
+	}
+	_, err := s.integer()
+	return err == nil
+}
+
+func (s *scalar) FloatE() (float64, error) {
+	var text string
+	switch v := s.v.(type) {
+	case float64:
+		return v, nil
+	case json.Number:
+		if !isNumberText(v.String()) {
+			return 0, fmt.Errorf("%w: %q is not a number", ErrTypeMismatch, v)
+		}
+		text = v.String()
+	case string:
+		if !isNumberText(v) {
+			return 0, fmt.Errorf("%w: %q is not a number", ErrTypeMismatch, v)
+		}
+		text = v
+	default:
+		return 0, s.notNumberError()
+	}
+
+	n, err := strconv.ParseFloat(text, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		return 0, fmt.Errorf("%w: %s overflows float64", ErrOverflow, text)
+	}
+	return n, err
+}
+
+func (s *scalar) IntE() (int64, error) {
+	n, err := s.integer()
+	if err != nil {
+		return 0, err
+	}
+	i, accuracy := n.Int64()
+	if accuracy != big.Exact {
+		return 0, fmt.Errorf("%w: %s overflows int64", ErrOverflow, n.Text('g', -1))
+	}
+	return i, nil
+}
+
+func (s *scalar) UintE() (uint64, error) {
+	n, err := s.integer()
+	if err != nil {
+		return 0, err
+	}
+	i, accuracy := n.Uint64()
+	if accuracy != big.Exact {
+		return 0, fmt.Errorf("%w: %s overflows uint64", ErrOverflow, n.Text('g', -1))
+	}
+	return i, nil
+}
+
+// integer returns the exact number represented by the scalar, including numeric strings.
+// It returns an error when the value is not numeric or it has a fractional part.
+func (s *scalar) integer() (*big.Float, error) {
+	var n *big.Float
+	switch v := s.v.(type) {
+	case float64:
+		if math.IsNaN(v) {
+			return nil, fmt.Errorf("%w: NaN", ErrNotInteger)
+		}
+		n = new(big.Float).SetFloat64(v)
+	case json.Number:
+		if !isNumberText(v.String()) {
+			return nil, fmt.Errorf("%w: %q is not a number", ErrTypeMismatch, v)
+		}
+		n, _ = parseNumber(v.String())
+	case string:
+		if !isNumberText(v) {
+			return nil, fmt.Errorf("%w: %q is not a number", ErrTypeMismatch, v)
+		}
+		n, _ = parseNumber(v)
+	default:
+		return nil, s.notNumberError()
+	}
+
+	if !n.IsInt() {
+		return nil, fmt.Errorf("%w: %s", ErrNotInteger, n.Text('g', -1))
+	}
+	return n, nil
+}
+
+// isNumberText determines whether the text is a valid json number.
+func isNumberText(text string) bool {
+	return text != "" && (text[0] == '-' || (text[0] >= '0' && text[0] <= '9')) && json.Valid([]byte(text))
+}
+
+func (s *scalar) notNumberError() error {
+	switch {
+	case !s.Exists():
+		return ErrNotFound
+	case s.v == nil:
+		return fmt.Errorf("%w: null is not a number", ErrTypeMismatch)
+	default:
+		return fmt.Errorf("%w: %T is not a number", ErrTypeMismatch, s.v)
+	}
+}
+
 func (s *scalar) String() string {
 	if s.v == nil {
 		return ""
@@ -103,6 +252,8 @@ func (s *scalar) String() string {
 	switch v := s.v.(type) {
 	case float64:
 		return strconv.FormatFloat(v, 'E', -1, 64)
+	case json.Number:
+		return v.String()
 	case string:
 		return v
 	case bool:
@@ -112,19 +263,34 @@ func (s *scalar) String() string {
 	}
 }
 
+func (s *scalar) Raw() []byte {
+	if !s.Exists() {
+		return nil
+	}
+	return rawJSON(s)
+}
+
+func (s *scalar) Decode(target any) error {
+	return decodeValue(s, target)
+}
+
+// Clone returns the same instance as scalar values can't be modified.
+func (s *scalar) Clone() Value {
+	return s
+}
+
 func (s *scalar) Value() any {
 	return s.v
 }
 
 func (s *scalar) Get(path string) Value {
-	if path[0] == '=' {
-		// Equality check
-		if path[1] == '"' { // string literal
-			stringValue := path[2 : len(path)-1] // remove quotes
-			if s.v == stringValue {
-				return s
-			}
-		}
+	return getPath(s, path)
+}
+
+func (s *scalar) get(c *component, next []component) Value {
+	if c.kind == conditionComponent && c.key == "" && c.cond.matches(s.v, s.Exists()) {
+		// Comparison check matched, continue with itself
+		return getComponents(s, next)
 	}
 
 	// No nested values
@@ -136,6 +302,10 @@ func (s *scalar) Set(_ string, _ any) Value {
 	return s
 }
 
+func (s *scalar) SetRaw(path string, jsonText string) (Value, error) {
+	return setRawPath(s, path, jsonText, decodeOptions{})
+}
+
 func (s *scalar) Delete(_ string) Value {
 	// Scalar values can't be deleted by path: noop
 	return s
@@ -1,11 +1,11 @@
 package jsonnav
 
-import (
-	"strconv"
-	"strings"
-)
+import "fmt"
 
-// Slice represents an array of values.
+// Slice represents an array of values, like the elements returned by Array() or the results of path expressions.
+//
+// Arrays obtained from a document with Get() reference their location in the document instead, so the changes made
+// on them are reflected in the document.
 type Slice []Value
 
 var _ Value = Slice{}
@@ -65,19 +65,40 @@ func (s Slice) Int() int64 {
 	return 0
 }
 
+// Uint returns 0 for slices.
+func (s Slice) Uint() uint64 {
+	return 0
+}
+
+// IsInt returns false for slices.
+func (s Slice) IsInt() bool {
+	return false
+}
+
+// FloatE returns an ErrTypeMismatch error for slices.
+func (s Slice) FloatE() (float64, error) {
+	return 0, fmt.Errorf("%w: an array is not a number", ErrTypeMismatch)
+}
+
+// IntE returns an ErrTypeMismatch error for slices.
+func (s Slice) IntE() (int64, error) {
+	return 0, fmt.Errorf("%w: an array is not a number", ErrTypeMismatch)
+}
+
+// UintE returns an ErrTypeMismatch error for slices.
+func (s Slice) UintE() (uint64, error) {
+	return 0, fmt.Errorf("%w: an array is not a number", ErrTypeMismatch)
+}
+
 // String returns an empty string for slices.
 func (s Slice) String() string {
 	return ""
 }
 
 // At returns the value at the specified index.
-// If the index is out of range, it returns an scalar with an internal nil value.
+// If the index is out of range, including negative indexes, it returns an scalar with an internal nil value.
 func (s Slice) At(index int) Value {
-	if index < 0 {
-		panic("index out of range")
-	}
-
-	if index >= len(s) {
+	if index < 0 || index >= len(s) {
 		return undefinedScalar
 	}
 	return s[index]
@@ -94,73 +115,80 @@ func (s Slice) Value() any {
 	return values
 }
 
-// Get searches for the specified path within the slice.
-func (s Slice) Get(path string) Value {
-	if path == "#" {
-		return &scalar{v: len(s)}
-	}
-	if strings.HasPrefix(path, "#(") {
-		// Apply the condition
-		key, remainingPath, _ := strings.Cut(path, ".")
-		childPath := key[2:]
-		shouldReturnList := false
-		if strings.HasSuffix(childPath, ")#") {
-			childPath = childPath[:len(childPath)-2] // remove trailing ")#" chars
-			shouldReturnList = true
-		} else {
-			childPath = childPath[:len(childPath)-1] // remove the parenthesis
-		}
+// Decode stores the slice in the Go value pointed by target.
+func (s Slice) Decode(target any) error {
+	return decodeValue(s, target)
+}
 
-		slice := s.applyChildConditionPath(childPath)
-		var result Value = slice
+// Clone returns a deep copy of the slice.
+func (s Slice) Clone() Value {
+	result := make(Slice, len(s))
+	for i, item := range s {
+		result[i] = item.Clone()
+	}
+	return result
+}
 
-		if !shouldReturnList {
-			// Return the first result
-			if len(slice) == 0 {
-				result = undefinedScalar
-			} else {
-				result = slice[0]
-			}
-		}
+// Raw returns the compact json encoding of the slice.
+func (s Slice) Raw() []byte {
+	return rawJSON(s)
+}
 
-		if remainingPath != "" {
-			result = result.Get(remainingPath)
-		}
+// Get searches for the specified path within the slice.
+func (s Slice) Get(path string) Value {
+	return getPath(s, path)
+}
 
-		return result
-	}
-	if strings.HasPrefix(path, "#.") {
+func (s Slice) get(c *component, next []component) Value {
+	switch c.kind {
+	case countComponent:
+		return getComponents(&scalar{v: float64(len(s))}, next)
+	case mapComponent:
 		// Apply the selection to the slice
-		childPath := path[2:]
-		newSlice := make(Slice, 0, len(s))
-		for _, value := range s {
-			v := value.Get(childPath)
-			if v.Exists() {
-				newSlice = append(newSlice, v)
+		return getComponents(s.mapComponents(c.sub), next)
+	case queryComponent:
+		// Apply the condition
+		slice := s.applyChildConditionPath(c.query)
+		if c.query.all {
+			if len(c.sub) > 0 {
+				// Apply the selection to each of the matches
+				slice = slice.mapComponents(c.sub)
 			}
+			return getComponents(slice, next)
 		}
-		return newSlice
-	}
 
-	indexString, remainingPath, _ := strings.Cut(path, ".")
-	index, err := strconv.Atoi(indexString)
-	if err == nil && index >= 0 && index < len(s) {
-		result := s[index]
-		if remainingPath != "" {
-			return result.Get(remainingPath)
+		// Return the first result
+		if len(slice) == 0 {
+			return undefinedScalar
 		}
-		return result
+		return getComponents(slice[0], next)
+	case keyComponent:
+		if c.index != -1 && c.index < len(s) {
+			return getComponents(s[c.index], next)
+		}
+	default:
 	}
 
 	// path is not supported on a slice
 	return undefinedScalar
 }
 
-func (s Slice) applyChildConditionPath(childPath string) Slice {
+// mapComponents evaluates the components on each element, omitting the results that don't exist.
+func (s Slice) mapComponents(components []component) Slice {
+	newSlice := make(Slice, 0, len(s))
+	for _, value := range s {
+		v := getComponents(value, components)
+		if v.Exists() {
+			newSlice = append(newSlice, v)
+		}
+	}
+	return newSlice
+}
+
+func (s Slice) applyChildConditionPath(q *query) Slice {
 	newSlice := make(Slice, 0, len(s))
 	for _, value := range s {
-		conditionValue := value.Get(childPath)
-		if conditionValue.Exists() {
+		if q.matches(value) {
 			// Return the complete child value if the condition matches
 			newSlice = append(newSlice, value)
 		}
@@ -170,24 +198,38 @@ func (s Slice) applyChildConditionPath(childPath string) Slice {
 
 // Set sets the value at the specified path.
 func (s Slice) Set(path string, rawValue any) Value {
-	key, remainingPath, _ := strings.Cut(path, ".")
+	return setPath(s, path, rawValue)
+}
+
+// SetRaw parses the json text and sets it at the specified path.
+func (s Slice) SetRaw(path string, jsonText string) (Value, error) {
+	return setRawPath(s, path, jsonText, decodeOptions{})
+}
+
+func (s Slice) set(components []component, rawValue any) Value {
+	return s.setItems(components, rawValue)
+}
+
+// setItems sets the value at the path components, returning the resulting items.
+func (s Slice) setItems(components []component, rawValue any) Slice {
+	c, next := &components[0], components[0].following(components[1:])
 
 	// Apply the rawValue to all slice elements
-	if key == "#" {
+	if c.kind == countComponent || c.kind == mapComponent {
 		result := s
 		for index, element := range result {
-			result[index] = element.Set(remainingPath, rawValue)
+			result[index] = setComponents(element, next, rawValue)
 		}
 		return result
 	}
 
-	index, err := strconv.Atoi(key)
-	if err != nil {
+	if c.kind != keyComponent || c.index == -1 {
 		// expected an index for a PathValueSlice: noop
 		return s
 	}
+	index := c.index
 	result := s
-	if remainingPath == "" {
+	if len(next) == 0 {
 		if rawValue == deleteValue {
 			// Remove by index
 			if index < len(result) {
@@ -197,7 +239,7 @@ func (s Slice) Set(path string, rawValue any) Value {
 		}
 		result = growSliceIfNeeded(result, index)
 		// Edit in place
-		result[index] = mustToPathValue(toJSONValue(rawValue))
+		result[index] = mustToPathValue(rawValue)
 		return result
 	}
 
@@ -205,13 +247,22 @@ func (s Slice) Set(path string, rawValue any) Value {
 	result = growSliceIfNeeded(result, index)
 	child := result[index]
 	if child.IsNull() {
-		child = mustToPathValue(createRawChild(remainingPath))
+		child = mustToPathValue(createRawChild(&next[0], false))
 	}
-	result[index] = child.Set(remainingPath, rawValue)
+	result[index] = setComponents(child, next, rawValue)
 
 	return result
 }
 
+// rawItems returns the raw json values of the elements.
+func (s Slice) rawItems() []any {
+	items := make([]any, 0, len(s))
+	for _, element := range s {
+		items = append(items, toRawValue(element))
+	}
+	return items
+}
+
 // Delete removes the value at the specified path.
 func (s Slice) Delete(path string) Value {
 	return s.Set(path, deleteValue)
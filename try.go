@@ -0,0 +1,199 @@
+package jsonnav
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// TryGet searches for the path within the value.
+// Unlike Get(), it returns an error when the path is not valid (*PathError) or the value can not be found
+// (ErrNotFound, ErrTypeMismatch or ErrIndexOutOfRange).
+func TryGet(value Value, path string) (Value, error) {
+	components, err := parsePath(path)
+	if err != nil {
+		return undefinedScalar, err
+	}
+	return tryGetComponents(value, components)
+}
+
+// TrySet sets the value at the path and returns the modified instance.
+// Unlike Set(), it returns an error when the path is not valid or the value can not be set, in which case the
+// value is not modified.
+func TrySet(value Value, path string, rawValue any) (Value, error) {
+	components, err := parsePath(path)
+	if err != nil {
+		return value, err
+	}
+	return trySetComponents(value, components, rawValue)
+}
+
+// TryDelete deletes the value at the path and returns the modified instance.
+// Unlike Delete(), it returns an error when the path is not valid or the value to delete does not exist, in which
+// case the value is not modified.
+func TryDelete(value Value, path string) (Value, error) {
+	return TrySet(value, path, deleteValue)
+}
+
+// TryGet is like Get() but returns an error when the value can not be found.
+func (p *Path) TryGet(value Value) (Value, error) {
+	return tryGetComponents(value, p.components)
+}
+
+// TrySet is like Set() but returns an error when the value can not be set.
+func (p *Path) TrySet(value Value, rawValue any) (Value, error) {
+	return trySetComponents(value, p.components, rawValue)
+}
+
+// TryDelete is like Delete() but returns an error when the value to delete does not exist.
+func (p *Path) TryDelete(value Value) (Value, error) {
+	return trySetComponents(value, p.components, deleteValue)
+}
+
+// tryGetComponents evaluates the components one by one to report the one that could not be found.
+func tryGetComponents(value Value, components []component) (Value, error) {
+	for i := range components {
+		c := &components[i]
+		if !isPackageValue(value) {
+			// Value implemented outside the package evaluates the remaining path
+			if result := value.Get(c.path); result.Exists() {
+				return result, nil
+			}
+			return undefinedScalar, fmt.Errorf("%w: %q", ErrNotFound, c.key)
+		}
+
+		result := getComponents(value, components[i:i+1])
+		if !result.Exists() {
+			return undefinedScalar, getError(value, c)
+		}
+		value = result
+	}
+	return value, nil
+}
+
+// getError returns the reason why the component could not be found in the value.
+func getError(value Value, c *component) error {
+	switch v := value.(type) {
+	case *LazyValue:
+		return getError(v.decoded(), c)
+	case *array:
+		return getError(v.slice(), c)
+	case Slice:
+		if c.kind == keyComponent {
+			return indexError(v, c)
+		}
+		if c.kind == conditionComponent {
+			return fmt.Errorf("%w: condition on key %q of an array", ErrTypeMismatch, c.key)
+		}
+	case *Map:
+		if c.kind == queryComponent {
+			return fmt.Errorf("%w: query %q on an object", ErrTypeMismatch, c.key)
+		}
+	case *scalar:
+		if v.Exists() && (c.kind == keyComponent || c.kind == countComponent || c.kind == mapComponent ||
+			c.kind == queryComponent) {
+			return fmt.Errorf("%w: key %q of a scalar value", ErrTypeMismatch, c.key)
+		}
+	}
+	return fmt.Errorf("%w: %q", ErrNotFound, c.key)
+}
+
+// indexError returns the error for a key component that can not be applied to the slice.
+func indexError(s Slice, c *component) error {
+	if _, err := strconv.Atoi(c.key); err == nil {
+		return fmt.Errorf("%w: index %s with length %d", ErrIndexOutOfRange, c.key, len(s))
+	}
+	return fmt.Errorf("%w: key %q of an array", ErrTypeMismatch, c.key)
+}
+
+// trySetComponents checks that the value can be set before modifying it.
+func trySetComponents(value Value, components []component, rawValue any) (Value, error) {
+	rawValue, err := toSetValue(rawValue)
+	if err != nil {
+		return value, err
+	}
+	if err := checkSet(value, components, rawValue == deleteValue); err != nil {
+		return value, err
+	}
+	return setComponents(value, components, rawValue), nil
+}
+
+// checkSet follows the same steps as set and returns the reason why the value can not be set or deleted.
+func checkSet(value Value, components []component, deleting bool) error {
+	if len(components) == 0 {
+		return nil
+	}
+
+	c, next := &components[0], components[0].following(components[1:])
+	switch v := value.(type) {
+	case *Map:
+		if c.kind != keyComponent && c.kind != countComponent && c.kind != mapComponent {
+			return fmt.Errorf("%w: can not set %q on an object", ErrTypeMismatch, c.key)
+		}
+		keys := []string{c.key}
+		if c.wildcard {
+			if keys = v.matchingKeys(c.key); len(keys) == 0 {
+				return fmt.Errorf("%w: no key matching %q", ErrNotFound, c.key)
+			}
+		}
+		for _, key := range keys {
+			rawChild, ok := v.m[key]
+			if !ok {
+				if deleting {
+					return fmt.Errorf("%w: %q", ErrNotFound, key)
+				}
+				// A new branch will be inserted
+				continue
+			}
+			if err := checkSet(mustToPathValue(rawChild), next, deleting); err != nil {
+				return err
+			}
+		}
+		return nil
+	case Slice:
+		if c.kind == countComponent || c.kind == mapComponent {
+			for _, element := range v {
+				if err := checkSet(element, next, deleting); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		if c.kind != keyComponent || c.index == -1 {
+			return indexError(v, c)
+		}
+		if c.index >= len(v) {
+			if deleting {
+				return indexError(v, c)
+			}
+			// The slice will grow
+			return nil
+		}
+		if v[c.index].IsNull() && !deleting {
+			// A new child will be created
+			return nil
+		}
+		return checkSet(v[c.index], next, deleting)
+	case *scalar:
+		if !v.Exists() {
+			return fmt.Errorf("%w: %q", ErrNotFound, c.key)
+		}
+		return fmt.Errorf("%w: can not set %q on a scalar value", ErrTypeMismatch, c.key)
+	case *LazyValue:
+		return checkSet(v.decoded(), components, deleting)
+	case *array:
+		return checkSet(v.slice(), components, deleting)
+	default:
+		// Value implemented outside the package
+		return nil
+	}
+}
+
+// isPackageValue determines whether the value is implemented within this package.
+func isPackageValue(value Value) bool {
+	switch value.(type) {
+	case *Map, Slice, *array, *scalar, *LazyValue:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,120 @@
+package jsonnav
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTryGet(t *testing.T) {
+	value := MustUnmarshalMap(friendsJSON)
+
+	t.Run("should return the value", func(t *testing.T) {
+		v, err := TryGet(value, "friends.#(last=\"Murphy\")#.first")
+		require.NoError(t, err)
+		require.Equal(t, []any{"Dale", "Jane"}, v.Value())
+
+		v, err = MustCompile("children.1").TryGet(value)
+		require.NoError(t, err)
+		require.Equal(t, "Alex", v.String())
+	})
+
+	t.Run("should return typed errors", func(t *testing.T) {
+		for _, tc := range []struct {
+			path string
+			err  error
+		}{
+			{"missing", ErrNotFound},
+			{"name.middle", ErrNotFound},
+			{"friends.#(age>100)", ErrNotFound},
+			{"children.3", ErrIndexOutOfRange},
+			{"children.-1", ErrIndexOutOfRange},
+			{"children.first", ErrTypeMismatch},
+			{"name.first.length", ErrTypeMismatch},
+			{"age.#", ErrTypeMismatch},
+			{"name.#(first=\"Tom\")", ErrTypeMismatch},
+		} {
+			v, err := TryGet(value, tc.path)
+			require.ErrorIs(t, err, tc.err, tc.path)
+			require.False(t, v.Exists())
+		}
+	})
+
+	t.Run("should return path errors", func(t *testing.T) {
+		_, err := TryGet(value, "friends.#(age>")
+		var pathErr *PathError
+		require.ErrorAs(t, err, &pathErr)
+	})
+}
+
+func TestTrySet(t *testing.T) {
+	t.Run("should set the value", func(t *testing.T) {
+		value := MustUnmarshalMap(friendsJSON)
+		result, err := TrySet(value, "name.middle", "J")
+		require.NoError(t, err)
+		require.Equal(t, "J", result.Get("name.middle").String())
+
+		_, err = TrySet(value, "friends.#.nets.0", "yt")
+		require.NoError(t, err)
+		require.Equal(t, []any{"yt", "yt", "yt"}, value.Get("friends.#.nets.0").Value())
+
+		_, err = MustCompile("children.5").TrySet(value, "Bob")
+		require.NoError(t, err)
+		require.Equal(t, 6, len(value.Get("children").Array()))
+	})
+
+	t.Run("should return typed errors without modifying the value", func(t *testing.T) {
+		for _, tc := range []struct {
+			value Value
+			path  string
+			err   error
+		}{
+			{MustUnmarshalScalar(`"a"`), "a", ErrTypeMismatch},
+			{MustUnmarshalMap(`{"a": "b"}`), "a.c", ErrTypeMismatch},
+			{MustUnmarshalMap(`{"a": [1]}`), "a.b", ErrTypeMismatch},
+			{MustUnmarshalMap(`{"a": [1]}`), "a.-1", ErrIndexOutOfRange},
+			{MustUnmarshalMap(`{"a": [{}, "b"]}`), "a.#.c", ErrTypeMismatch},
+			{MustUnmarshalMap(`{"a": [1]}`), "a.#(==1)", ErrTypeMismatch},
+			{MustUnmarshalMap(`{"a": 1}`), "b*.c", ErrNotFound},
+		} {
+			expected := tc.value.Value()
+			result, err := TrySet(tc.value, tc.path, "x")
+			require.ErrorIs(t, err, tc.err, tc.path)
+			require.Equal(t, expected, result.Value(), tc.path)
+		}
+	})
+
+	t.Run("should return an error for values that can't be represented in json", func(t *testing.T) {
+		value := MustUnmarshalMap(`{"a": 1}`)
+		_, err := TrySet(value, "b", func() {})
+		require.ErrorIs(t, err, ErrTypeMismatch)
+		require.False(t, value.Get("b").Exists())
+	})
+}
+
+func TestTryDelete(t *testing.T) {
+	t.Run("should delete the value", func(t *testing.T) {
+		value := MustUnmarshalMap(friendsJSON)
+		_, err := TryDelete(value, "name.first")
+		require.NoError(t, err)
+		require.False(t, value.Get("name.first").Exists())
+
+		_, err = MustCompile("friends.#.nets.0").TryDelete(value)
+		require.NoError(t, err)
+		require.Equal(t, []any{"fb", "tw", "tw"}, value.Get("friends.#.nets.0").Value())
+	})
+
+	t.Run("should return an error when the value does not exist", func(t *testing.T) {
+		value := MustUnmarshalMap(friendsJSON)
+		_, err := TryDelete(value, "name.middle")
+		require.ErrorIs(t, err, ErrNotFound)
+
+		_, err = TryDelete(value, "missing.a.b")
+		require.ErrorIs(t, err, ErrNotFound)
+		require.False(t, value.Get("missing").Exists())
+
+		_, err = TryDelete(value, "children.3")
+		require.ErrorIs(t, err, ErrIndexOutOfRange)
+		require.Equal(t, 3, len(value.Get("children").Array()))
+	})
+}
@@ -4,10 +4,6 @@ package jsonnav
 // For example: `value.Get("a.b")` will access the value in the object at the path "a.b".
 //
 // Get() and `Set()` methods partially supports GJSON syntax: https://github.com/tidwall/gjson/blob/master/SYNTAX.md
-//
-// Not supported expressions:
-// - Nested conditions like "friends.#(nets.#(=="fb"))#".
-// - Tilde comparison.
 type Value interface {
 	// Exists returns true if value exists.
 	Exists() bool
@@ -37,6 +33,9 @@ type Value interface {
 	// IsBool returns true if the value is a bool scalar.
 	IsBool() bool
 
+	// IsInt returns true if the value is a number scalar without a fractional part.
+	IsInt() bool
+
 	// Bool returns a boolean representation.
 	// When the value is not a boolean scalar, it returns false.
 	Bool() bool
@@ -49,6 +48,24 @@ type Value interface {
 	// When the value is not a number scalar, it returns 0.
 	Int() int64
 
+	// Uint returns an unsigned integer representation.
+	// When the value is not a number scalar or it's negative, it returns 0.
+	Uint() uint64
+
+	// FloatE returns the float64 representation of a number or a numeric string.
+	// It returns ErrTypeMismatch when the value is not numeric and ErrOverflow when it's out of the float64 range.
+	FloatE() (float64, error)
+
+	// IntE returns the int64 representation of a number or a numeric string.
+	// It returns ErrTypeMismatch when the value is not numeric, ErrNotInteger when it has a fractional part and
+	// ErrOverflow when it's out of the int64 range.
+	IntE() (int64, error)
+
+	// UintE returns the uint64 representation of a number or a numeric string.
+	// It returns ErrTypeMismatch when the value is not numeric, ErrNotInteger when it has a fractional part and
+	// ErrOverflow when it's negative or out of the uint64 range.
+	UintE() (uint64, error)
+
 	// String returns a string representation of the value.
 	// If the internal value is a bool or float64 scalar, it will be converted to string.
 	// If the internal value is a JSON object or array, it will return empty string.
@@ -58,20 +75,40 @@ type Value interface {
 	//
 	//	bool, for JSON booleans
 	//	float64, for JSON numbers
-	//	Number, for JSON numbers
+	//	json.Number, for JSON numbers when decoding with UseNumber()
 	//	string, for JSON string literals
 	//	nil, for JSON null
 	//	map[string]any, for JSON objects
 	//	[]any, for JSON arrays
 	Value() any
 
+	// Raw returns the json text of the value.
+	// For values decoded with UnmarshalLazy(), it's the exact source text of the nodes reached through keys, wildcard
+	// keys, indexes and queries, until they are modified. Results computed by modifiers, multipaths, `#.` and `#(...)#`
+	// are compact json encodings of the source nodes they contain, as for any other value.
+	// It returns nil when the value does not exist.
+	Raw() []byte
+
+	// Decode stores the value in the Go value pointed by target, following the encoding/json rules for struct tags,
+	// pointers, maps, slices and unmarshalers, without encoding the value to json.
+	// It returns a *DecodeError including the path of the value that could not be decoded, or ErrNotFound when the
+	// value does not exist.
+	Decode(target any) error
+
 	// Get searches for the specified path.
 	Get(path string) Value
 
 	// Set sets the value in the provided path and returns the modified instance.
 	// If the path does not exist, it will be created.
+	// The value can be any Go value that can be represented in json, including other Value instances, and it's
+	// converted as with FromGo(). Values that can't be represented in json are ignored.
 	Set(path string, rawValue any) Value
 
+	// SetRaw parses the json text and sets it in the provided path, returning the modified instance.
+	// It returns an error when the json text is not valid or a *PathError when the path is not valid, in which case
+	// the value is not modified.
+	SetRaw(path string, jsonText string) (Value, error)
+
 	// Delete deletes the value in the provided path and returns the modified instance.
 	Delete(path string) Value
 
@@ -85,6 +122,10 @@ type Value interface {
 	// Map returns back a map of values. The result should be a JSON object.
 	// If the result is not a JSON object, the return value will be an empty map.
 	Map() map[string]Value
+
+	// Clone returns a deep copy of the value, so the changes made on the copy are not reflected in the original
+	// value and vice versa.
+	Clone() Value
 }
 
 // An internal type to mark the set operation as a delete.
@@ -1,6 +1,9 @@
 package jsonnav
 
 import (
+	"encoding/json"
+	"io"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -19,6 +22,17 @@ const testJSON = `{
 	"nestedArray": [{"name": "Alice", "attrs": {"age": 33}}, {"name": "Bob"}]
 }`
 
+const friendsJSON = `{
+	"name": {"first": "Tom", "last": "Anderson"},
+	"age": 37,
+	"children": ["Sara", "Alex", "Jack"],
+	"friends": [
+		{"first": "Dale", "last": "Murphy", "age": 44, "score": 4.5, "nets": ["ig", "fb", "tw"]},
+		{"first": "Roger", "last": "Craig", "age": 68, "score": 3, "nets": ["fb", "tw"]},
+		{"first": "Jane", "last": "Murphy", "age": 47, "score": 5, "nets": ["ig", "tw"]}
+	]
+}`
+
 func TestGet(t *testing.T) {
 	value, err := UnmarshalMap(testJSON)
 	require.NoError(t, err)
@@ -32,7 +46,7 @@ func TestGet(t *testing.T) {
 		require.Equal(t, &scalar{v: nil}, value.Get("nil"))
 		require.True(t, value.Get("nil").Exists())
 		require.False(t, value.Get("NOT_EXISTS").Exists())
-		require.Equal(t, &Map{m: map[string]any{"a": 1.1}}, value.Get("object"))
+		require.Equal(t, &Map{m: map[string]any{"a": 1.1}, refs: value.refs}, value.Get("object"))
 	})
 
 	t.Run("should support slices", func(t *testing.T) {
@@ -46,6 +60,7 @@ func TestGet(t *testing.T) {
 		require.Equal(t, "a", s.Array().At(0).String())
 		require.Equal(t, &scalar{"b"}, s.Array().At(1))
 		require.Equal(t, undefinedScalar, s.Array().At(2))
+		require.Equal(t, undefinedScalar, s.Array().At(-1))
 	})
 
 	t.Run("should return nested values", func(t *testing.T) {
@@ -77,6 +92,170 @@ func TestGet(t *testing.T) {
 		require.Equal(t, &scalar{v: "a"}, value.Get(`array.#(="a")`))
 	})
 
+	t.Run("should support comparison operators in conditions", func(t *testing.T) {
+		friends := MustUnmarshalMap(friendsJSON)
+		require.Equal(t, []any{"Craig", "Murphy"}, friends.Get("friends.#(age>45)#.last").Value())
+		require.Equal(t, []any{"Murphy", "Craig", "Murphy"}, friends.Get("friends.#(age>=44)#.last").Value())
+		require.Equal(t, []any{"Dale"}, friends.Get("friends.#(age<45)#.first").Value())
+		require.Equal(t, []any{"Dale", "Jane"}, friends.Get("friends.#(age<=47)#.first").Value())
+		require.Equal(t, []any{"Roger"}, friends.Get(`friends.#(last!="Murphy")#.first`).Value())
+		require.Equal(t, "Dale", friends.Get(`friends.#(last=="Murphy").first`).String())
+		require.Equal(t, "Roger", friends.Get(`friends.#(first>"Jane").first`).String())
+		require.Equal(t, []any{"Dale", "Jane"}, friends.Get("friends.#(score>=4.5)#.first").Value())
+		require.Equal(t, "Roger", friends.Get("friends.#(score<4.5).first").String())
+		require.False(t, friends.Get("friends.#(age>100)").Exists())
+		require.Equal(t, Slice{}, friends.Get("friends.#(age>100)#"))
+		require.Equal(t, []any{"Sara", "Jack"}, friends.Get(`children.#(!="Alex")#`).Value())
+		require.Equal(t, []any{"Alex", "Jack"}, friends.Get(`children.#(<"Sara")#`).Value())
+
+		// Values of different types are not ordered
+		require.False(t, friends.Get(`friends.#(age>"10")`).Exists())
+		require.Equal(t, 3, len(friends.Get(`friends.#(age!="44")#`).Array()))
+
+		// Booleans
+		bools := From([]any{true, false})
+		require.Equal(t, []any{true}, bools.Get(`#(>false)#`).Value())
+		require.False(t, bools.Get(`#(<false)`).Exists())
+	})
+
+	t.Run("should only support equality in conditions on object keys", func(t *testing.T) {
+		require.Equal(t, value, value.Get("float64==123"))
+		require.Equal(t, "value", value.Get("float64=123.string").String())
+		require.False(t, value.Get("float64==124").Exists())
+
+		// Other operators are part of the key
+		keys := MustUnmarshalMap(`{"discount%": 10, "a<b": 1, "x>y": 2, "a!": 3}`)
+		require.Equal(t, 10.0, keys.Get("discount%").Float())
+		require.Equal(t, 1.0, keys.Get("a<b").Float())
+		require.Equal(t, 2.0, keys.Get("x>y").Float())
+		require.Equal(t, keys, keys.Get("a!=3"))
+	})
+
+	t.Run("should support pattern matching in conditions", func(t *testing.T) {
+		friends := MustUnmarshalMap(friendsJSON)
+		require.Equal(t, []any{"Dale"}, friends.Get(`friends.#(first%"D*")#.first`).Value())
+		require.Equal(t, []any{"Roger", "Jane"}, friends.Get(`friends.#(first!%"D*")#.first`).Value())
+		require.Equal(t, "Jane", friends.Get(`friends.#(first%"*ne").first`).String())
+		require.Equal(t, "Jane", friends.Get(`friends.#(first%"J?n?").first`).String())
+		require.Equal(t, "Roger", friends.Get(`friends.#(last%"?r*g").first`).String())
+		require.False(t, friends.Get(`friends.#(first%"J?n").first`).Exists())
+		require.Equal(t, []any{"Sara", "Jack"}, friends.Get(`children.#(%"*a*")#`).Value())
+		require.Equal(t, []any{"Sara", "Alex", "Jack"}, friends.Get(`children.#(%"*")#`).Value())
+
+		// Non string values don't match patterns
+		require.False(t, friends.Get(`friends.#(age%"4*")`).Exists())
+		require.Equal(t, 3, len(friends.Get(`friends.#(age!%"4*")#`).Array()))
+
+		// Escaped wildcards
+		value := MustUnmarshalMap(`{"items": [{"name": "a*b"}, {"name": "axb"}]}`)
+		require.Equal(t, []any{"a*b"}, value.Get(`items.#(name%"a\*b")#.name`).Value())
+		require.Equal(t, []any{"a*b", "axb"}, value.Get(`items.#(name%"a*b")#.name`).Value())
+	})
+
+	t.Run("should support nested conditions", func(t *testing.T) {
+		friends := MustUnmarshalMap(friendsJSON)
+		require.Equal(t, []any{"Dale", "Roger"}, friends.Get(`friends.#(nets.#(=="fb"))#.first`).Value())
+		require.Equal(t, "Dale", friends.Get(`friends.#(nets.#(=="fb")).first`).String())
+		require.Equal(t, []any{"Dale", "Jane"}, friends.Get(`friends.#(nets.#(%"i*"))#.first`).Value())
+		require.Equal(t, []any{"Roger"}, friends.Get(`friends.#(nets.#(!%"f*")=="tw")#.first`).Value())
+		require.Equal(t, []any{"Dale", "Jane"}, friends.Get(`friends.#(nets.#(%"i*")#)#.first`).Value())
+		require.Equal(t, []any{"Dale", "Roger"}, friends.Get(`friends.#(nets.#(=="fb")#)#.first`).Value())
+		require.False(t, friends.Get(`friends.#(nets.#(=="yt"))`).Exists())
+		require.Equal(t, Slice{}, friends.Get(`friends.#(nets.#(=="yt")#)#`))
+
+		// Literals containing parenthesis
+		value := MustUnmarshalMap(`{"items": [{"name": "a)b", "tags": ["x(y"]}, {"name": "c"}]}`)
+		require.Equal(t, "a)b", value.Get(`items.#(tags.#(=="x(y")).name`).String())
+		require.Equal(t, "a)b", value.Get(`items.#(name=="a)b").name`).String())
+	})
+
+	t.Run("should support tilde comparisons", func(t *testing.T) {
+		value := MustUnmarshalMap(`{"vals": [
+			{"a": 1, "b": "data"},
+			{"a": 2, "b": true},
+			{"a": 3, "b": false},
+			{"a": 4, "b": "0"},
+			{"a": 5, "b": 0},
+			{"a": 6, "b": "1"},
+			{"a": 7, "b": "true"},
+			{"a": 8, "b": null},
+			{"a": 9}
+		]}`)
+		require.Equal(t, []any{2.0, 6.0, 7.0}, value.Get("vals.#(b==~true)#.a").Value())
+		require.Equal(t, []any{1.0, 3.0, 4.0, 5.0, 8.0, 9.0}, value.Get("vals.#(b==~false)#.a").Value())
+		require.Equal(t, []any{1.0, 3.0, 4.0, 5.0, 8.0, 9.0}, value.Get("vals.#(b!=~true)#.a").Value())
+		require.Equal(t, []any{8.0, 9.0}, value.Get("vals.#(b==~null)#.a").Value())
+		require.Equal(t, []any{1.0, 2.0, 3.0, 4.0, 5.0, 6.0, 7.0, 8.0}, value.Get("vals.#(b==~*)#.a").Value())
+		require.Equal(t, []any{9.0}, value.Get("vals.#(b!=~*)#.a").Value())
+		require.Equal(t, 2.0, value.Get("vals.#(b=~true).a").Float())
+
+		// Conditions on maps and scalars
+		require.Equal(t, value, value.Get("missing==~null"))
+		require.Equal(t, value, value.Get("missing==~false"))
+		require.False(t, value.Get("missing==~*").Exists())
+		require.Equal(t, "true", value.Get("vals.6.b==~true.b").String())
+		require.Equal(t, "1", value.Get("vals.5.b").Get("==~true").String())
+	})
+
+	t.Run("should support wildcards in keys", func(t *testing.T) {
+		value := MustUnmarshalMap(`{
+			"v2_config": {"name": "second"},
+			"v1_config": {"name": "first"},
+			"child": "a",
+			"cild": "b"
+		}`)
+		require.Equal(t, "first", value.Get("v*_config.name").String())
+		require.Equal(t, "second", value.Get("v2*.name").String())
+		require.Equal(t, "first", value.Get("v?_config").Get("name").String())
+		require.Equal(t, "a", value.Get("c?ild").String())
+		require.Equal(t, "a", value.Get("c*ld").String())
+		require.Equal(t, "b", value.Get("ci*").String())
+		require.Equal(t, "a", value.Get("*").String())
+		require.False(t, value.Get("v?_conf").Exists())
+		require.False(t, value.Get("z*").Exists())
+	})
+
+	t.Run("should support escaped characters in keys", func(t *testing.T) {
+		value := MustUnmarshalMap(`{
+			"fav.movie": "Deer Hunter",
+			"user@example.com": {"name": "John"},
+			"a*": 1,
+			"ab": 2,
+			"#": "hash",
+			"a=b": "eq",
+			"list": [{"fav.movie": "Platoon"}, {"fav.movie": "Heat"}]
+		}`)
+		require.Equal(t, "Deer Hunter", value.Get(`fav\.movie`).String())
+		require.Equal(t, "John", value.Get(`user@example\.com.name`).String())
+		require.Equal(t, 1.0, value.Get(`a\*`).Float())
+		require.Equal(t, "hash", value.Get(`\#`).String())
+		require.Equal(t, "eq", value.Get(`a\=b`).String())
+		require.Equal(t, []any{"Platoon", "Heat"}, value.Get(`list.#.fav\.movie`).Value())
+		require.Equal(t, "Heat", value.Get(`list.#(fav\.movie=="Heat").fav\.movie`).String())
+		require.False(t, value.Get(`fav.movie`).Exists())
+
+		for _, key := range []string{"fav.movie", "user@example.com", "a*", "#", "a=b"} {
+			require.True(t, value.Get(Escape(key)).Exists(), key)
+		}
+	})
+
+	t.Run("should support pipes", func(t *testing.T) {
+		friends := MustUnmarshalMap(friendsJSON)
+		require.Equal(t, int64(2), friends.Get(`friends.#(last="Murphy")#|#`).Int())
+		require.Equal(t, 3.0, friends.Get("friends.#").Float())
+		require.Equal(t, "Dale", friends.Get(`friends.#(last="Murphy")#|0.first`).String())
+		require.Equal(t, []any{"Dale", "Jane"}, friends.Get(`friends.#(last="Murphy")#.first`).Value())
+		require.Equal(t, Slice{}, friends.Get(`friends.#(last="Murphy")#.0`))
+		require.Equal(t, "Jane", friends.Get(`friends.#(last="Murphy")#|1|first`).String())
+		require.Equal(t, []any{"ig", "fb", "tw"}, friends.Get("friends.#.nets|0").Value())
+		require.Equal(t, []any{"ig", "fb", "ig"}, friends.Get("friends.#.nets.0").Value())
+		require.Equal(t, []any{"Jane", "Roger", "Dale"}, friends.Get("friends.#.first|@reverse").Value())
+		require.Equal(t, []any{"ig", "fb", "tw", "fb", "tw", "ig", "tw"}, friends.Get("friends.#.nets|@flatten").Value())
+		require.Equal(t, int64(2), friends.Get(`friends.#(age>45)#.last|#`).Int())
+		require.Equal(t, "Tom", friends.Get("name|first").String())
+		require.Equal(t, "Tom", friends.Get("age==37|name.first").String())
+	})
+
 	t.Run("should get array values using indices", func(t *testing.T) {
 		require.Equal(t, "a", value.Get("array.0").String())
 		require.Equal(t, "b", value.Get("array.1").String())
@@ -167,6 +346,32 @@ func TestSet(t *testing.T) {
 			value.Get("nestedArray").Value())
 	})
 
+	t.Run("should set all matching keys when using wildcards", func(t *testing.T) {
+		value := MustUnmarshalMap(`{"v1_config": {"name": "first"}, "v2_config": {"name": "second"}, "other": 1}`)
+		value.Set("v*_config.enabled", true)
+		value.Set("v?_config.name", "updated")
+		value.Set("z*.name", "noop")
+		require.Equal(t, map[string]any{
+			"v1_config": map[string]any{"name": "updated", "enabled": true},
+			"v2_config": map[string]any{"name": "updated", "enabled": true},
+			"other":     1.0,
+		}, value.Value())
+	})
+
+	t.Run("should set keys containing escaped characters", func(t *testing.T) {
+		value := MustUnmarshalMap(`{"fav.movie": "Deer Hunter", "list": []}`)
+		value.Set(`fav\.movie`, "Platoon")
+		value.Set(`user@example\.com.name`, "John")
+		value.Set(`a\*`, 1)
+		value.Set(`list.0.fav\.movie`, "Heat")
+		require.Equal(t, map[string]any{
+			"fav.movie":        "Platoon",
+			"user@example.com": map[string]any{"name": "John"},
+			"a*":               1.0,
+			"list":             []any{map[string]any{"fav.movie": "Heat"}},
+		}, value.Value())
+	})
+
 	t.Run("should set nested elements in a map", func(t *testing.T) {
 		value := MustUnmarshalMap(testJSON)
 		value.Get("object").Set("b", 2.0)
@@ -174,6 +379,181 @@ func TestSet(t *testing.T) {
 			"object": map[string]any{"a": 1.1, "b": 2.0},
 		})
 	})
+
+	t.Run("should convert Go values", func(t *testing.T) {
+		value := MustUnmarshalMap(`{"list": [1]}`)
+		value.Set("tags", []string{"x", "y"})
+		value.Set("counts", map[string]int{"a": 1})
+		value.Set("small", int8(-3))
+		value.Set("point", struct {
+			X float32 `json:"x"`
+			Y uint    `json:"y,omitempty"`
+		}{X: 1.5})
+		value.Set("list.1", []int{2})
+		value.Set("invalid", make(chan int))
+		require.Equal(t, map[string]any{
+			"tags":   []any{"x", "y"},
+			"counts": map[string]any{"a": 1.0},
+			"small":  -3.0,
+			"point":  map[string]any{"x": 1.5},
+			"list":   []any{1.0, []any{2.0}},
+		}, value.Value())
+		require.Equal(t, "y", value.Get("tags.1").String())
+		require.Equal(t, 2.0, value.Get("list.1.0").Float())
+	})
+
+	t.Run("should set values", func(t *testing.T) {
+		value := MustUnmarshalMap(`{"a": {}}`)
+		other := MustUnmarshalMap(`{"b": [1, {"c": true}]}`)
+		value.Set("a.other", other)
+		value.Set("a.list", other.Get("b"))
+		value.Set("a.scalar", other.Get("b.1.c"))
+		value.Set("a.missing", other.Get("missing"))
+		lazy, err := UnmarshalLazy([]byte(`{"d": 1}`))
+		require.NoError(t, err)
+		value.Set("a.lazy", lazy)
+
+		require.True(t, value.Get("a.other.b.1.c").Bool())
+		require.True(t, value.Get("a.list.1.c").Bool())
+		require.True(t, value.Get("a.scalar").Bool())
+		require.True(t, value.Get("a.missing").IsNull())
+		require.Equal(t, 1.0, value.Get("a.lazy.d").Float())
+		str, err := Marshal(value)
+		require.NoError(t, err)
+		require.Equal(t,
+			`{"a":{"lazy":{"d":1},"list":[1,{"c":true}],"missing":null,"other":{"b":[1,{"c":true}]},"scalar":true}}`,
+			str)
+	})
+
+	t.Run("should only contain json values after setting values", func(t *testing.T) {
+		lazy, err := UnmarshalLazy([]byte(`{"x": 1, "y": {"z": [true]}}`))
+		require.NoError(t, err)
+		value := MustUnmarshalMap(`{}`)
+		value.Set("raw", json.RawMessage(`{"b": [1, null]}`))
+		value.Set("lazy", lazy.Get("x"))
+		value.Set("lazyObject", lazy.Get("y"))
+		value.Set("ordered", must(Unmarshal(`{"b": 1, "a": {"c": 2}}`, PreserveOrder())))
+		value.Set("list", []any{must(Unmarshal(`{"d": 3}`, PreserveOrder()))})
+
+		require.Equal(t, map[string]any{
+			"raw":        map[string]any{"b": []any{1.0, nil}},
+			"lazy":       1.0,
+			"lazyObject": map[string]any{"z": []any{true}},
+			"ordered":    map[string]any{"b": 1.0, "a": map[string]any{"c": 2.0}},
+			"list":       []any{map[string]any{"d": 3.0}},
+		}, value.Value())
+		require.True(t, value.Get("lazy==1").Exists())
+		require.True(t, value.Get("raw.b.0").Exists())
+		require.Equal(t, 2.0, value.Get("ordered.a.c").Float())
+
+		value.Set("invalid", json.RawMessage(`{"b": `))
+		require.False(t, value.Get("invalid").Exists())
+	})
+
+	t.Run("should set raw json", func(t *testing.T) {
+		value := MustUnmarshalMap(`{"a": [1]}`)
+		result, err := value.SetRaw("a.1", `{"b": [true, null]}`)
+		require.NoError(t, err)
+		require.Same(t, value, result)
+		require.Equal(t, []any{1.0, map[string]any{"b": []any{true, nil}}}, value.Get("a").Value())
+
+		_, err = value.SetRaw("c", `{"b": `)
+		require.Error(t, err)
+		_, err = value.SetRaw("c", `1 2`)
+		require.Error(t, err)
+		_, err = value.SetRaw("c.", `1`)
+		var pathErr *PathError
+		require.ErrorAs(t, err, &pathErr)
+		require.Equal(t, "c.", pathErr.Path)
+		require.False(t, value.Get("c").Exists())
+
+		ordered := must(Unmarshal(`{"z": 1}`, PreserveOrder()))
+		_, err = ordered.SetRaw("a", `{"y": 1, "x": 2}`)
+		require.NoError(t, err)
+		require.Equal(t, []string{"y", "x"}, ordered.Get("a").(*Map).Keys())
+
+		_, err = value.SetRaw("d", `[1, 2]`)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), value.Get("d.#").Int())
+	})
+
+	t.Run("should reflect changes made on arrays obtained from the document", func(t *testing.T) {
+		value := MustUnmarshalMap(`{"tags": ["a", "b"], "matrix": [[1, 2], [3]], "items": [{"id": 1}]}`)
+		tags := value.Get("tags")
+		tags.Set("0", "x")
+		tags.Set("3", "d")
+		require.Equal(t, []any{"x", "b", nil, "d"}, value.Get("tags").Value())
+		require.Equal(t, value.Get("tags").Value(), tags.Value())
+
+		// Other values referencing the same array see the changes
+		other := value.Get("tags")
+		tags.Set("4", "e")
+		require.Equal(t, int64(5), other.Get("#").Int())
+		other.Set("0", "y")
+		require.Equal(t, []any{"y", "b", nil, "d", "e"}, value.Get("tags").Value())
+		value.Set("tags.5", "f")
+		tags.Set("1", "c")
+		require.Equal(t, []any{"y", "c", nil, "d", "e", "f"}, value.Get("tags").Value())
+		require.Equal(t, value.Get("tags").Value(), other.Value())
+
+		row := value.Get("matrix.1")
+		row.Set("1", 4)
+		value.Get("matrix").Array()[0].Set("2", 5)
+		require.Equal(t, []any{[]any{1.0, 2.0, 5.0}, []any{3.0, 4.0}}, value.Get("matrix").Value())
+
+		value.Get("items").Set("0.name", "first")
+		value.Get("items").Set("1.id", 2)
+		require.Equal(t, []any{map[string]any{"id": 1.0, "name": "first"}, map[string]any{"id": 2.0}},
+			value.Get("items").Value())
+
+		// Arrays removed from the document are detached
+		value.Delete("tags")
+		tags.Set("0", "y")
+		require.False(t, value.Get("tags").Exists())
+		require.Equal(t, "y", tags.Get("0").String())
+	})
+
+	t.Run("should share the arrays obtained through different paths", func(t *testing.T) {
+		value := MustUnmarshalMap(`{"a": {"tags": ["x"]}, "m": [[1], [2]]}`)
+		tags := value.Get("a.tags")
+		nested := value.Get("a").Get("tags")
+		tags.Set("1", "y")
+		nested.Set("0", "z")
+		require.Equal(t, []any{"z", "y"}, value.Get("a.tags").Value())
+
+		row := value.Get("m.1")
+		value.Get("m").Array()[1].Set("1", 3)
+		row.Set("0", 4)
+		require.Equal(t, []any{[]any{1.0}, []any{4.0, 3.0}}, value.Get("m").Value())
+
+		// The row moved to another index, it's detached
+		value.Get("m").Delete("0")
+		row.Set("2", 5)
+		require.Equal(t, []any{[]any{4.0, 3.0}}, value.Get("m").Value())
+		require.Equal(t, []any{4.0, 3.0, 5.0}, row.Value())
+	})
+
+	t.Run("should not write into arrays that replaced the obtained array", func(t *testing.T) {
+		value := MustUnmarshalMap(`{"a": [[1, 2], [3]], "tags": ["a"]}`)
+		inner := value.Get("a.0")
+		value.Get("a").Delete("0")
+		inner.Set("0", 100)
+		require.Equal(t, []any{[]any{3.0}}, value.Get("a").Value())
+		require.Equal(t, []any{100.0, 2.0}, inner.Value())
+
+		tags := value.Get("tags")
+		value.Set("tags", []string{"new"})
+		tags.Set("0", "x")
+		require.Equal(t, []any{"new"}, value.Get("tags").Value())
+		require.Equal(t, []any{"x"}, tags.Value())
+	})
+
+	t.Run("should modify root arrays in place", func(t *testing.T) {
+		value := must(Unmarshal(`[1, [2]]`))
+		value.Set("2", 3)
+		value.Get("1").Set("1", 4)
+		require.Equal(t, []any{1.0, []any{2.0, 4.0}, 3.0}, value.Value())
+	})
 }
 
 func TestDelete(t *testing.T) {
@@ -189,6 +569,23 @@ func TestDelete(t *testing.T) {
 		require.Equal(t, []any{"b"}, value.Get("array").Value())
 		require.Equal(t, map[string]any{"name": "Alice"}, value.Get("nestedArray.#(name=Alice)").Value())
 
+		// Delete all matching keys
+		value = MustUnmarshalMap(`{"v1_config": {"name": "a"}, "v2_config": {"name": "b", "id": 2}, "other": 1}`)
+		value.Delete("v*_config.name")
+		require.Equal(t, map[string]any{
+			"v1_config": map[string]any{},
+			"v2_config": map[string]any{"id": 2.0},
+			"other":     1.0,
+		}, value.Value())
+		value.Delete("v?_*")
+		require.Equal(t, map[string]any{"other": 1.0}, value.Value())
+
+		// Delete keys containing escaped characters
+		value = MustUnmarshalMap(`{"fav.movie": "Deer Hunter", "a*": 1, "ab": 2}`)
+		value.Delete(`fav\.movie`)
+		value.Delete(`a\*`)
+		require.Equal(t, map[string]any{"ab": 2.0}, value.Value())
+
 		// Delete all nested elements in an array
 		value = MustUnmarshalMap(testJSON)
 		value.Delete("nestedArray.#.name")
@@ -199,4 +596,258 @@ func TestDelete(t *testing.T) {
 			},
 			value.Get("nestedArray").Value())
 	})
+
+	t.Run("should delete from arrays obtained from the document", func(t *testing.T) {
+		value := MustUnmarshalMap(`{"tags": ["a", "b", "c"], "nested": {"list": [[1, 2]]}}`)
+		value.Get("tags").Delete("1")
+		value.Get("nested.list.0").Delete("0")
+		require.Equal(t, map[string]any{
+			"tags":   []any{"a", "c"},
+			"nested": map[string]any{"list": []any{[]any{2.0}}},
+		}, value.Value())
+	})
+}
+
+func TestNumericAccessors(t *testing.T) {
+	value := MustUnmarshalMap(`{
+		"int": 42, "negative": -7, "decimal": 2.5, "big": 1e20, "text": "123", "decimalText": "1.5", "name": "Tom",
+		"bool": true, "null": null, "list": [1], "obj": {}
+	}`)
+
+	t.Run("should check for integers", func(t *testing.T) {
+		require.True(t, value.Get("int").IsInt())
+		require.True(t, value.Get("negative").IsInt())
+		require.True(t, value.Get("big").IsInt())
+		require.False(t, value.Get("decimal").IsInt())
+		require.False(t, value.Get("text").IsInt())
+		require.False(t, value.Get("list").IsInt())
+		require.False(t, value.Get("obj").IsInt())
+		require.False(t, value.Get("missing").IsInt())
+	})
+
+	t.Run("should return unsigned integers", func(t *testing.T) {
+		require.Equal(t, uint64(42), value.Get("int").Uint())
+		require.Equal(t, uint64(0), value.Get("negative").Uint())
+		require.Equal(t, uint64(2), value.Get("decimal").Uint())
+		require.Equal(t, uint64(123), value.Get("text").Uint())
+		require.Equal(t, uint64(0), value.Get("list").Uint())
+
+		v, err := Unmarshal(`[18446744073709551615]`, UseNumber())
+		require.NoError(t, err)
+		require.Equal(t, uint64(18446744073709551615), v.Get("0").Uint())
+
+		v = must(Unmarshal(`[18446744073709551615, 1e30, 1.8e19]`))
+		require.Equal(t, uint64(0), v.Get("0").Uint())
+		require.Equal(t, uint64(0), v.Get("1").Uint())
+		require.Equal(t, uint64(18000000000000000000), v.Get("2").Uint())
+		v, err = Unmarshal(`[1e30]`, UseNumber())
+		require.NoError(t, err)
+		require.Equal(t, uint64(0), v.Get("0").Uint())
+	})
+
+	t.Run("should return numbers with errors", func(t *testing.T) {
+		i, err := value.Get("int").IntE()
+		require.NoError(t, err)
+		require.Equal(t, int64(42), i)
+
+		u, err := value.Get("text").UintE()
+		require.NoError(t, err)
+		require.Equal(t, uint64(123), u)
+
+		f, err := value.Get("decimalText").FloatE()
+		require.NoError(t, err)
+		require.Equal(t, 1.5, f)
+
+		v, err := Unmarshal(`[18446744073709551615, 9007199254740993]`, UseNumber())
+		require.NoError(t, err)
+		u, err = v.Get("0").UintE()
+		require.NoError(t, err)
+		require.Equal(t, uint64(18446744073709551615), u)
+		i, err = v.Get("1").IntE()
+		require.NoError(t, err)
+		require.Equal(t, int64(9007199254740993), i)
+	})
+
+	t.Run("should report invalid numbers", func(t *testing.T) {
+		for _, tc := range []struct {
+			path string
+			fn   func(Value) error
+			err  error
+		}{
+			{"decimal", intE, ErrNotInteger},
+			{"decimalText", uintE, ErrNotInteger},
+			{"big", intE, ErrOverflow},
+			{"negative", uintE, ErrOverflow},
+			{"name", intE, ErrTypeMismatch},
+			{"name", floatE, ErrTypeMismatch},
+			{"bool", floatE, ErrTypeMismatch},
+			{"null", intE, ErrTypeMismatch},
+			{"list", intE, ErrTypeMismatch},
+			{"obj", floatE, ErrTypeMismatch},
+			{"missing", uintE, ErrNotFound},
+		} {
+			require.ErrorIs(t, tc.fn(value.Get(tc.path)), tc.err, tc.path)
+		}
+
+		_, err := From("1e400").FloatE()
+		require.ErrorIs(t, err, ErrOverflow)
+		_, err = From("NaN").FloatE()
+		require.ErrorIs(t, err, ErrTypeMismatch)
+
+		invalid := &scalar{v: json.Number("abc")}
+		require.False(t, invalid.IsInt())
+		_, err = invalid.IntE()
+		require.ErrorIs(t, err, ErrTypeMismatch)
+		_, err = invalid.UintE()
+		require.ErrorIs(t, err, ErrTypeMismatch)
+		_, err = invalid.FloatE()
+		require.ErrorIs(t, err, ErrTypeMismatch)
+	})
+}
+
+func intE(v Value) error {
+	_, err := v.IntE()
+	return err
+}
+
+func uintE(v Value) error {
+	_, err := v.UintE()
+	return err
+}
+
+func floatE(v Value) error {
+	_, err := v.FloatE()
+	return err
+}
+
+func TestPreserveOrder(t *testing.T) {
+	const doc = `{"zeta":1,"alpha":{"y":true,"x":null},"mid":[{"b":1,"a":2}],"beta":"b"}`
+
+	t.Run("should keep the source order", func(t *testing.T) {
+		value, err := UnmarshalMap(doc, PreserveOrder())
+		require.NoError(t, err)
+		require.Equal(t, []string{"zeta", "alpha", "mid", "beta"}, value.Keys())
+		require.Equal(t, []any{"y", "x"}, value.Get("alpha.@keys").Value())
+		require.Equal(t, []any{true, nil}, value.Get("alpha.@values").Value())
+		require.Equal(t, 1.0, value.Get("mid.0.b").Float())
+		require.Equal(t, 1.0, value.Get("*a").Float())
+
+		str, err := Marshal(value)
+		require.NoError(t, err)
+		require.Equal(t, doc, str)
+
+		var keys []string
+		for key, child := range value.All() {
+			keys = append(keys, key)
+			require.True(t, child.Exists())
+		}
+		require.Equal(t, []string{"zeta", "alpha", "mid", "beta"}, keys)
+	})
+
+	t.Run("should keep the insertion order when setting and deleting", func(t *testing.T) {
+		value, err := Unmarshal(doc, PreserveOrder())
+		require.NoError(t, err)
+		value.Set("zeta", 2)
+		value.Set("gamma.c", "new")
+		value.Set("gamma.a", "new")
+		value.Set("alpha.w", 0)
+		value.Delete("mid")
+
+		str, err := Marshal(value)
+		require.NoError(t, err)
+		require.Equal(t, `{"zeta":2,"alpha":{"y":true,"x":null,"w":0},"beta":"b","gamma":{"c":"new","a":"new"}}`, str)
+
+		var sb strings.Builder
+		require.NoError(t, Encode(&sb, value))
+		require.Equal(t, str+"\n", sb.String())
+		sb.Reset()
+		require.NoError(t, Encode(&sb, value, WithSortedKeys()))
+		require.Equal(t, `{"alpha":{"w":0,"x":null,"y":true},"beta":"b","gamma":{"a":"new","c":"new"},"zeta":2}`+"\n",
+			sb.String())
+	})
+
+	t.Run("should return go maps as values", func(t *testing.T) {
+		value, err := Unmarshal(doc, PreserveOrder())
+		require.NoError(t, err)
+		require.Equal(t, MustUnmarshalMap(doc).Value(), value.Value())
+		require.Equal(t, map[string]any{"b": 1.0, "a": 2.0}, value.Get("mid.0").Value())
+	})
+
+	t.Run("should apply to decoders", func(t *testing.T) {
+		dec := NewDecoder(strings.NewReader(`{"b":1,"a":2} {"d":[{"y":1,"x":2}],"c":3}`), PreserveOrder())
+		var docs []string
+		for dec.More() {
+			value, err := dec.Decode()
+			require.NoError(t, err)
+			str, err := Marshal(value)
+			require.NoError(t, err)
+			docs = append(docs, str)
+		}
+		require.Equal(t, []string{`{"b":1,"a":2}`, `{"d":[{"y":1,"x":2}],"c":3}`}, docs)
+
+		_, err := Decode(strings.NewReader(`{"b":1,"a":`), PreserveOrder())
+		require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		_, err = UnmarshalMap(`[1]`, PreserveOrder())
+		require.Error(t, err)
+	})
+}
+
+func TestClone(t *testing.T) {
+	t.Run("should return independent copies", func(t *testing.T) {
+		template := MustUnmarshalMap(`{"name": "template", "tags": ["a"], "nested": {"list": [{"id": 1}]}}`)
+		first := template.Clone()
+		second := template.Clone()
+		first.Set("name", "first")
+		first.Get("tags").Set("1", "b")
+		first.Set("nested.list.0.id", 2)
+		second.Delete("nested")
+
+		require.Equal(t, must(Unmarshal(`{"name": "template", "tags": ["a"], "nested": {"list": [{"id": 1}]}}`)).Value(),
+			template.Value())
+		require.Equal(t, map[string]any{
+			"name":   "first",
+			"tags":   []any{"a", "b"},
+			"nested": map[string]any{"list": []any{map[string]any{"id": 2.0}}},
+		}, first.Value())
+		require.Equal(t, map[string]any{"name": "template", "tags": []any{"a"}}, second.Value())
+
+		template.Set("tags.0", "x")
+		require.Equal(t, "a", first.Get("tags.0").String())
+	})
+
+	t.Run("should clone all kinds of values", func(t *testing.T) {
+		value := must(Unmarshal(`{"b": [[1]], "a": 2}`, PreserveOrder()))
+		clone := value.Clone()
+		require.Equal(t, []string{"b", "a"}, clone.(*Map).Keys())
+		clone.Set("c", 3)
+		require.False(t, value.Get("c").Exists())
+
+		array := value.Get("b.0").Clone()
+		array.Set("0", 5)
+		require.Equal(t, 1.0, value.Get("b.0.0").Float())
+		require.Equal(t, 5.0, array.Get("0").Float())
+
+		slice := value.Get("b.#.0").Clone()
+		require.Equal(t, []any{1.0}, slice.Value())
+		require.Equal(t, 2.0, value.Get("a").Clone().Float())
+		require.False(t, value.Get("missing").Clone().Exists())
+
+		lazy, err := UnmarshalLazy([]byte(`{"a": [1]}`))
+		require.NoError(t, err)
+		lazy.Set("b", true)
+		lazyClone := lazy.Clone()
+		lazyClone.Set("a.0", 2)
+		require.Equal(t, `{"a":[1],"b":true}`, string(lazy.Raw()))
+		require.Equal(t, `{"a":[2],"b":true}`, string(lazyClone.Raw()))
+	})
+
+	t.Run("should copy Go values", func(t *testing.T) {
+		m := map[string]any{"tags": []any{"a"}, "counts": map[string]int{"x": 1}}
+		value := CopyFrom(m)
+		value.Set("tags.0", "b")
+		value.Set("other", true)
+		require.Equal(t, map[string]any{"tags": []any{"a"}, "counts": map[string]int{"x": 1}}, m)
+		require.Equal(t, map[string]any{"tags": []any{"b"}, "counts": map[string]any{"x": 1.0}, "other": true},
+			value.Value())
+	})
 }